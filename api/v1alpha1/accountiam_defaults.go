@@ -0,0 +1,181 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Default values of the AccountIAM spec. They reproduce the settings the
+// operator used before the spec was configurable.
+const (
+	DefaultRedisSize         int32 = 3
+	DefaultRedisVersion            = "1.2.8"
+	DefaultRedisScaleConfig        = "medium"
+	DefaultExternalRedisPort int32 = 6379
+
+	DefaultDatabaseHost            = "common-service-db-rw"
+	DefaultDatabasePort      int32 = 5432
+	DefaultDatabaseName            = "account_iam"
+	DefaultDatabaseSchema          = "accountiam"
+	DefaultDatabaseUser            = "user_accountiam"
+	DefaultDatabaseSSLMode         = "prefer"
+	DefaultDatabaseSuperuser       = "common-service-db-superuser"
+
+	DefaultAccountIAMReplicas int32 = 1
+	DefaultAccountName              = "default-account"
+	DefaultServiceName              = "default-service"
+	DefaultServiceIDName            = "default-serviceid"
+	DefaultSubscriptionName         = "default-subscription"
+
+	DefaultUIReplicas      int32 = 1
+	DefaultDeploymentCloud       = "IBM_CLOUD"
+	DefaultNodeEnv               = "production"
+	DefaultConfigEnv             = "dev"
+
+	DefaultConsoleRoute = "cp-console"
+	DefaultAPIKeyName   = "default-apikey"
+
+	DefaultOperandRequestPolicy = CleanupPolicyDelete
+	DefaultSecretPolicy         = CleanupPolicyRetain
+)
+
+// DefaultAccountIAMResources returns the default resources of the Account IAM container
+func DefaultAccountIAMResources() *corev1.ResourceRequirements {
+	return &corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("300m"),
+			corev1.ResourceMemory: resource.MustParse("400Mi"),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("1500m"),
+			corev1.ResourceMemory: resource.MustParse("800Mi"),
+		},
+	}
+}
+
+// DefaultUIResources returns the default resources of the Account IAM console containers
+func DefaultUIResources() *corev1.ResourceRequirements {
+	return &corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("200m"),
+			corev1.ResourceMemory: resource.MustParse("128Mi"),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("500m"),
+			corev1.ResourceMemory: resource.MustParse("512Mi"),
+		},
+	}
+}
+
+// SetDefaults fills every unset field of the spec with its default value.
+// Hostnames are left empty since they are derived from the cluster at reconcile time.
+func (s *AccountIAMSpec) SetDefaults() {
+	if s.Redis.Mode == "" {
+		s.Redis.Mode = RedisModeManaged
+	}
+	if s.Redis.External != nil && s.Redis.External.Port == 0 {
+		s.Redis.External.Port = DefaultExternalRedisPort
+	}
+	// The Rediscp CR settings only apply to the Managed mode
+	if s.Redis.Mode == RedisModeManaged {
+		if s.Redis.Size == 0 {
+			s.Redis.Size = DefaultRedisSize
+		}
+		if s.Redis.Version == "" {
+			s.Redis.Version = DefaultRedisVersion
+		}
+		if s.Redis.ScaleConfig == "" {
+			s.Redis.ScaleConfig = DefaultRedisScaleConfig
+		}
+	}
+
+	if s.Database.Host == "" {
+		s.Database.Host = DefaultDatabaseHost
+	}
+	if s.Database.Port == 0 {
+		s.Database.Port = DefaultDatabasePort
+	}
+	if s.Database.Name == "" {
+		s.Database.Name = DefaultDatabaseName
+	}
+	if s.Database.Schema == "" {
+		s.Database.Schema = DefaultDatabaseSchema
+	}
+	if s.Database.User == "" {
+		s.Database.User = DefaultDatabaseUser
+	}
+	if s.Database.SSLMode == "" {
+		s.Database.SSLMode = DefaultDatabaseSSLMode
+	}
+	if s.Database.SuperuserSecretRef == nil {
+		s.Database.SuperuserSecretRef = &corev1.LocalObjectReference{Name: DefaultDatabaseSuperuser}
+	}
+
+	if s.AccountIAM.Replicas == nil {
+		replicas := DefaultAccountIAMReplicas
+		s.AccountIAM.Replicas = &replicas
+	}
+	if s.AccountIAM.Resources == nil {
+		s.AccountIAM.Resources = DefaultAccountIAMResources()
+	}
+	if s.AccountIAM.AccountName == "" {
+		s.AccountIAM.AccountName = DefaultAccountName
+	}
+	if s.AccountIAM.ServiceName == "" {
+		s.AccountIAM.ServiceName = DefaultServiceName
+	}
+	if s.AccountIAM.ServiceIDName == "" {
+		s.AccountIAM.ServiceIDName = DefaultServiceIDName
+	}
+	if s.AccountIAM.SubscriptionName == "" {
+		s.AccountIAM.SubscriptionName = DefaultSubscriptionName
+	}
+
+	if s.UI.Replicas == nil {
+		replicas := DefaultUIReplicas
+		s.UI.Replicas = &replicas
+	}
+	if s.UI.Resources == nil {
+		s.UI.Resources = DefaultUIResources()
+	}
+	if s.UI.DeploymentCloud == "" {
+		s.UI.DeploymentCloud = DefaultDeploymentCloud
+	}
+	if s.UI.NodeEnv == "" {
+		s.UI.NodeEnv = DefaultNodeEnv
+	}
+	if s.UI.ConfigEnv == "" {
+		s.UI.ConfigEnv = DefaultConfigEnv
+	}
+
+	if s.Routing.ConsoleRoute == "" {
+		s.Routing.ConsoleRoute = DefaultConsoleRoute
+	}
+
+	if s.Integration.APIKeyName == "" {
+		s.Integration.APIKeyName = DefaultAPIKeyName
+	}
+
+	if s.Uninstall.OperandRequestPolicy == "" {
+		s.Uninstall.OperandRequestPolicy = DefaultOperandRequestPolicy
+	}
+	if s.Uninstall.SecretPolicy == "" {
+		s.Uninstall.SecretPolicy = DefaultSecretPolicy
+	}
+}
@@ -18,19 +18,267 @@ package v1alpha1
 
 import (
 	odlm "github.com/IBM/operand-deployment-lifecycle-manager/v4/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
-// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
-
 // AccountIAMSpec defines the desired state of AccountIAM
 type AccountIAMSpec struct {
-	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	// Redis configures the Redis session store used by the Account IAM UI
+	// +optional
+	Redis RedisSpec `json:"redis,omitempty"`
+
+	// Database configures the PostgreSQL database used by Account IAM
+	// +optional
+	Database DatabaseSpec `json:"database,omitempty"`
+
+	// AccountIAM configures the Account IAM service and the default account it bootstraps
+	// +optional
+	AccountIAM AccountIAMServiceSpec `json:"accountIAM,omitempty"`
+
+	// UI configures the Account IAM console
+	// +optional
+	UI UISpec `json:"ui,omitempty"`
+
+	// Routing configures the hostnames exposed for Account IAM and its console
+	// +optional
+	Routing RoutingSpec `json:"routing,omitempty"`
+
+	// Integration configures the integration between Account IAM and IM
+	// +optional
+	Integration IntegrationSpec `json:"integration,omitempty"`
+
+	// Uninstall configures the cleanup performed when the AccountIAM is deleted
+	// +optional
+	Uninstall UninstallSpec `json:"uninstall,omitempty"`
+}
+
+// Redis modes of the Account IAM UI session store
+const (
+	// RedisModeManaged deploys a Rediscp CR through the ibm-redis-cp-operator
+	RedisModeManaged = "Managed"
+	// RedisModeExternal uses an existing Redis described in spec.redis.external
+	RedisModeExternal = "External"
+	// RedisModeDisabled runs the UI without Redis
+	RedisModeDisabled = "Disabled"
+)
+
+// RedisSpec defines the Redis session store settings
+type RedisSpec struct {
+	// Mode selects how Redis is provided to the Account IAM UI.
+	// The size, version, scaleConfig and storage settings only apply to the Managed mode.
+	// Leaving the Managed mode deletes the Rediscp CR and the certificates created for it.
+	// +optional
+	// +kubebuilder:validation:Enum=Managed;External;Disabled
+	Mode string `json:"mode,omitempty"`
+
+	// External describes the existing Redis used in the External mode
+	// +optional
+	External *ExternalRedisSpec `json:"external,omitempty"`
+
+	// Size is the number of Redis members in the Rediscp CR
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Size int32 `json:"size,omitempty"`
+
+	// Version is the Redis version requested from the Rediscp CR
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// ScaleConfig is the scale profile of the Redis members
+	// +optional
+	// +kubebuilder:validation:Enum=small;medium;large
+	ScaleConfig string `json:"scaleConfig,omitempty"`
+
+	// Storage configures persistent storage for the Redis members.
+	// Redis runs without persistent storage when it is not set.
+	// +optional
+	Storage *RedisStorageSpec `json:"storage,omitempty"`
+}
+
+// ExternalRedisSpec defines the connection to an existing Redis
+type ExternalRedisSpec struct {
+	// Host is the Redis host name
+	Host string `json:"host"`
+
+	// Port is the Redis TLS port
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port,omitempty"`
+
+	// PasswordSecretRef selects the key of a secret holding the Redis password
+	// +optional
+	PasswordSecretRef *corev1.SecretKeySelector `json:"passwordSecretRef,omitempty"`
+
+	// CASecretRef selects the key of a secret holding the CA certificate of Redis
+	// +optional
+	CASecretRef *corev1.SecretKeySelector `json:"caSecretRef,omitempty"`
+}
+
+// RedisStorageSpec defines the persistent storage of the Redis members
+type RedisStorageSpec struct {
+	// StorageClassName is the storage class of the Redis volumes. The cluster default is used when empty
+	// +optional
+	StorageClassName string `json:"storageClassName,omitempty"`
+
+	// Size is the size of each Redis volume
+	// +optional
+	Size *resource.Quantity `json:"size,omitempty"`
+}
+
+// DatabaseSpec defines the PostgreSQL connection settings
+type DatabaseSpec struct {
+	// Host is the PostgreSQL host name
+	// +optional
+	Host string `json:"host,omitempty"`
+
+	// Port is the PostgreSQL port
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port,omitempty"`
+
+	// Name is the name of the Account IAM database
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Schema is the database schema used by Account IAM
+	// +optional
+	Schema string `json:"schema,omitempty"`
+
+	// User is the database user used by Account IAM
+	// +optional
+	User string `json:"user,omitempty"`
+
+	// SSLMode is the PostgreSQL sslmode used by Account IAM
+	// +optional
+	// +kubebuilder:validation:Enum=disable;allow;prefer;require;verify-ca;verify-full
+	SSLMode string `json:"sslMode,omitempty"`
+
+	// CASecretRef selects the key of a secret holding the CA bundle of the PostgreSQL server
+	// +optional
+	CASecretRef *corev1.SecretKeySelector `json:"caSecretRef,omitempty"`
+
+	// PasswordSecretRef selects the key of a secret holding the password of the database user.
+	// A password is generated into the user-mgmt-bootstrap secret when it is not set.
+	// +optional
+	PasswordSecretRef *corev1.SecretKeySelector `json:"passwordSecretRef,omitempty"`
+
+	// SuperuserSecretRef is the secret with the username and password keys used to create the database
+	// +optional
+	SuperuserSecretRef *corev1.LocalObjectReference `json:"superuserSecretRef,omitempty"`
 
-	// Foo is an example field of AccountIAM. Edit accountiam_types.go to remove/update
-	Foo string `json:"foo,omitempty"`
+	// PreProvisioned skips the creation of the database and its user when they already exist.
+	// A name, schema or user other than the defaults must be pre-provisioned.
+	// +optional
+	PreProvisioned bool `json:"preProvisioned,omitempty"`
+}
+
+// AccountIAMServiceSpec defines the Account IAM service settings
+type AccountIAMServiceSpec struct {
+	// Replicas is the number of Account IAM pods
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Resources are the compute resources of the Account IAM container
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// AccountName is the name of the default account created in Account IAM
+	// +optional
+	AccountName string `json:"accountName,omitempty"`
+
+	// ServiceName is the name of the default service created in Account IAM
+	// +optional
+	ServiceName string `json:"serviceName,omitempty"`
+
+	// ServiceIDName is the name of the default service ID created in Account IAM
+	// +optional
+	ServiceIDName string `json:"serviceIDName,omitempty"`
+
+	// SubscriptionName is the name of the default subscription created in Account IAM
+	// +optional
+	SubscriptionName string `json:"subscriptionName,omitempty"`
+}
+
+// UISpec defines the Account IAM console settings
+type UISpec struct {
+	// Replicas is the number of pods of each Account IAM console deployment
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Resources are the compute resources of the Account IAM console containers
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// DeploymentCloud is the DEPLOYMENT_CLOUD value passed to the console
+	// +optional
+	DeploymentCloud string `json:"deploymentCloud,omitempty"`
+
+	// NodeEnv is the NODE_ENV value passed to the console
+	// +optional
+	NodeEnv string `json:"nodeEnv,omitempty"`
+
+	// ConfigEnv is the CONFIG_ENV value passed to the console
+	// +optional
+	ConfigEnv string `json:"configEnv,omitempty"`
+}
+
+// RoutingSpec defines the hostnames of Account IAM and its console.
+// Hostnames left empty are derived from the IM console route.
+type RoutingSpec struct {
+	// ConsoleRoute is the name of the IM console route in the AccountIAM namespace
+	// +optional
+	ConsoleRoute string `json:"consoleRoute,omitempty"`
+
+	// APIHostname is the hostname of the Account IAM route
+	// +optional
+	APIHostname string `json:"apiHostname,omitempty"`
+
+	// ConsoleHostname is the hostname of the Account IAM console routes
+	// +optional
+	ConsoleHostname string `json:"consoleHostname,omitempty"`
+}
+
+// IntegrationSpec defines how Account IAM is integrated with IM
+type IntegrationSpec struct {
+	// OIDCIssuerURL is the OIDC issuer configured in IM for Account IAM.
+	// It defaults to the IM identity provider behind the console route.
+	// +optional
+	OIDCIssuerURL string `json:"oidcIssuerURL,omitempty"`
+
+	// APIKeyName is the name of the API key created for the IM integration
+	// +optional
+	APIKeyName string `json:"apiKeyName,omitempty"`
+}
+
+// Cleanup policies of the resources left behind by a deleted AccountIAM
+const (
+	// CleanupPolicyDelete deletes the resource with the AccountIAM
+	CleanupPolicyDelete = "Delete"
+	// CleanupPolicyRetain keeps the resource after the AccountIAM is deleted
+	CleanupPolicyRetain = "Retain"
+)
+
+// UninstallSpec defines what happens to the resources that are not garbage
+// collected with the AccountIAM. The OIDC issuer URL set by the operator in the
+// CommonService CR is always reverted to its previous value.
+type UninstallSpec struct {
+	// OperandRequestPolicy controls whether the OperandRequest of the Account IAM
+	// prerequisites is deleted with the AccountIAM or retained
+	// +optional
+	// +kubebuilder:validation:Enum=Delete;Retain
+	OperandRequestPolicy string `json:"operandRequestPolicy,omitempty"`
+
+	// SecretPolicy controls whether the secrets created without an owner, such as
+	// user-mgmt-bootstrap holding the database password, are deleted with the AccountIAM or retained
+	// +optional
+	// +kubebuilder:validation:Enum=Delete;Retain
+	SecretPolicy string `json:"secretPolicy,omitempty"`
 }
 
 // // ManagedResourceStatus represents the status of a resource managed by AccountIAM
@@ -52,15 +300,61 @@ type AccountIAMSpec struct {
 // 	ManagedResources []ManagedResourceStatus `json:"managedResources,omitempty"`
 // }
 
+// RedisStatus reports the Redis mode and the settings applied to the Rediscp CR
+type RedisStatus struct {
+	Mode             string `json:"mode,omitempty"`
+	Size             int32  `json:"size,omitempty"`
+	Version          string `json:"version,omitempty"`
+	ScaleConfig      string `json:"scaleConfig,omitempty"`
+	StorageClassName string `json:"storageClassName,omitempty"`
+	StorageSize      string `json:"storageSize,omitempty"`
+}
+
+// Condition types of AccountIAM. There is one condition per reconcile phase
+// and the aggregated Ready, Progressing and Degraded conditions.
+const (
+	ConditionTypeReady                 = "Ready"
+	ConditionTypeProgressing           = "Progressing"
+	ConditionTypeDegraded              = "Degraded"
+	ConditionTypePrerequisitesReady    = "PrerequisitesReady"
+	ConditionTypeOperandResourcesReady = "OperandResourcesReady"
+	ConditionTypeIMConfigured          = "IMConfigured"
+	ConditionTypeUIReady               = "UIReady"
+)
+
+// Condition reasons of AccountIAM
+const (
+	ConditionReasonReconciled  = "Reconciled"
+	ConditionReasonReconciling = "Reconciling"
+	ConditionReasonPending     = "Pending"
+	ConditionReasonFailed      = "Failed"
+)
+
 // AccountIAMStatus defines the observed state of AccountIAM
 type AccountIAMStatus struct {
 
 	// Import the operandstatus from odlm
 	Service odlm.OperandStatus `json:"service,omitempty"`
+
+	// Redis reports the effective Redis settings
+	Redis RedisStatus `json:"redis,omitempty"`
+
+	// ObservedGeneration is the generation of the spec the conditions were computed for
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions report the state of each reconcile phase and the overall readiness
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+//+kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].reason"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // AccountIAM is the Schema for the accountiams API
 type AccountIAM struct {
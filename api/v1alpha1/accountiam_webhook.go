@@ -17,35 +17,63 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/IBM/ibm-user-management-operator/version"
 )
 
 // log is for logging in this package.
 var accountiamlog = logf.Log.WithName("accountiam-resource")
 
+// accountiamReader looks up the existing AccountIAMs while validating a new one
+var accountiamReader client.Reader
+
+// DefaultsVersionAnnotation records the version of the operator which applied the spec defaults
+const DefaultsVersionAnnotation = "operator.ibm.com/ibm-user-management-operator.defaults-version"
+
+// MaxRedisSize bounds the number of Redis members requested from the Rediscp CR
+const MaxRedisSize int32 = 9
+
 // SetupWebhookWithManager will setup the manager to manage the webhooks
 func (r *AccountIAM) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	accountiamReader = mgr.GetAPIReader()
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
 		Complete()
 }
 
-// TODO(user): EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+//+kubebuilder:webhook:path=/mutate-operator-ibm-com-v1alpha1-accountiam,mutating=true,failurePolicy=fail,sideEffects=None,groups=operator.ibm.com,resources=accountiams,verbs=create;update,versions=v1alpha1,name=maccountiam.kb.io,admissionReviewVersions=v1
 
 var _ webhook.Defaulter = &AccountIAM{}
 
-// Default implements webhook.Defaulter so a webhook will be registered for the type
+// Default implements webhook.Defaulter so a webhook will be registered for the type.
+// It stores the effective spec the reconciler uses, and the operator version which defaulted it.
 func (r *AccountIAM) Default() {
 	accountiamlog.Info("default", "name", r.Name)
 
-	// TODO(user): fill in your defaulting logic.
+	r.Spec.SetDefaults()
+
+	annotations := r.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[DefaultsVersionAnnotation] = version.Version
+	r.SetAnnotations(annotations)
 }
 
-// TODO(user): change verbs to "verbs=create;update;delete" if you want to enable deletion validation.
 // NOTE: The 'path' attribute must follow a specific pattern and should not be modified directly here.
 // Modifying the path for an invalid path can cause API server errors; failing to locate the webhook.
 //+kubebuilder:webhook:path=/validate-operator-ibm-com-v1alpha1-accountiam,mutating=false,failurePolicy=fail,sideEffects=None,groups=operator.ibm.com,resources=accountiams,verbs=create;update,versions=v1alpha1,name=vaccountiam.kb.io,admissionReviewVersions=v1
@@ -56,22 +84,273 @@ var _ webhook.Validator = &AccountIAM{}
 func (r *AccountIAM) ValidateCreate() (admission.Warnings, error) {
 	accountiamlog.Info("validate create", "name", r.Name)
 
-	// TODO(user): fill in your validation logic upon object creation.
-	return nil, nil
+	allErrs := r.validateSpec()
+	allErrs = append(allErrs, r.validateDatabaseObjects()...)
+	singletonErr, err := r.validateSingleton()
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	if singletonErr != nil {
+		allErrs = append(allErrs, singletonErr)
+	}
+	return r.ignoredFieldWarnings(), r.toInvalidError(allErrs)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
 func (r *AccountIAM) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
 	accountiamlog.Info("validate update", "name", r.Name)
 
-	// TODO(user): fill in your validation logic upon object update.
-	return nil, nil
+	// Let the finalizer be released whatever the spec holds
+	if r.DeletionTimestamp != nil {
+		return nil, nil
+	}
+
+	oldAccountIAM, ok := old.(*AccountIAM)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected an AccountIAM but got a %T", old))
+	}
+
+	allErrs := r.validateSpec()
+	// An AccountIAM admitted before the database objects were checked stays updatable
+	if len(oldAccountIAM.validateDatabaseObjects()) == 0 {
+		allErrs = append(allErrs, r.validateDatabaseObjects()...)
+	}
+	allErrs = append(allErrs, r.validateImmutableFields(oldAccountIAM)...)
+	return r.ignoredFieldWarnings(), r.toInvalidError(allErrs)
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
 func (r *AccountIAM) ValidateDelete() (admission.Warnings, error) {
 	accountiamlog.Info("validate delete", "name", r.Name)
+	return nil, nil
+}
 
-	// TODO(user): fill in your validation logic upon object deletion.
+// validateSingleton rejects a second AccountIAM in the namespace
+func (r *AccountIAM) validateSingleton() (*field.Error, error) {
+	if accountiamReader == nil {
+		return nil, nil
+	}
+
+	accountIAMs := &AccountIAMList{}
+	if err := accountiamReader.List(context.TODO(), accountIAMs, client.InNamespace(r.Namespace)); err != nil {
+		return nil, err
+	}
+	for _, existing := range accountIAMs.Items {
+		if existing.Name != r.Name {
+			return field.Forbidden(field.NewPath("metadata", "namespace"),
+				fmt.Sprintf("AccountIAM %s already exists in namespace %s, only one AccountIAM is allowed per namespace", existing.Name, r.Namespace)), nil
+		}
+	}
 	return nil, nil
 }
+
+// validateSpec checks the ranges of the spec values and the well-formedness of its secret references
+func (r *AccountIAM) validateSpec() field.ErrorList {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	redis := r.Spec.Redis
+	redisPath := specPath.Child("redis")
+	if redis.Size != 0 && (redis.Size < 1 || redis.Size > MaxRedisSize) {
+		allErrs = append(allErrs, field.Invalid(redisPath.Child("size"), redis.Size,
+			fmt.Sprintf("must be between 1 and %d", MaxRedisSize)))
+	}
+	if redis.Storage != nil && redis.Storage.Size != nil && redis.Storage.Size.Sign() <= 0 {
+		allErrs = append(allErrs, field.Invalid(redisPath.Child("storage", "size"), redis.Storage.Size.String(), "must be greater than zero"))
+	}
+	if redis.Mode == RedisModeExternal && redis.External == nil {
+		allErrs = append(allErrs, field.Required(redisPath.Child("external"), "must be set when the Redis mode is External"))
+	}
+	if redis.External != nil {
+		externalPath := redisPath.Child("external")
+		if redis.External.Host == "" {
+			allErrs = append(allErrs, field.Required(externalPath.Child("host"), ""))
+		}
+		allErrs = append(allErrs, validatePort(externalPath.Child("port"), redis.External.Port)...)
+		allErrs = append(allErrs, validateSecretKeySelector(externalPath.Child("passwordSecretRef"), redis.External.PasswordSecretRef)...)
+		allErrs = append(allErrs, validateSecretKeySelector(externalPath.Child("caSecretRef"), redis.External.CASecretRef)...)
+	}
+
+	database := r.Spec.Database
+	databasePath := specPath.Child("database")
+	allErrs = append(allErrs, validatePort(databasePath.Child("port"), database.Port)...)
+	allErrs = append(allErrs, validateSecretKeySelector(databasePath.Child("caSecretRef"), database.CASecretRef)...)
+	allErrs = append(allErrs, validateSecretKeySelector(databasePath.Child("passwordSecretRef"), database.PasswordSecretRef)...)
+	if database.SuperuserSecretRef != nil {
+		allErrs = append(allErrs, validateSecretName(databasePath.Child("superuserSecretRef", "name"), database.SuperuserSecretRef.Name)...)
+	}
+
+	allErrs = append(allErrs, validateReplicas(specPath.Child("accountIAM", "replicas"), r.Spec.AccountIAM.Replicas)...)
+	allErrs = append(allErrs, validateResources(specPath.Child("accountIAM", "resources"), r.Spec.AccountIAM.Resources)...)
+	allErrs = append(allErrs, validateReplicas(specPath.Child("ui", "replicas"), r.Spec.UI.Replicas)...)
+	allErrs = append(allErrs, validateResources(specPath.Child("ui", "resources"), r.Spec.UI.Resources)...)
+
+	routingPath := specPath.Child("routing")
+	for _, hostname := range []struct {
+		path  *field.Path
+		value string
+	}{
+		{routingPath.Child("apiHostname"), r.Spec.Routing.APIHostname},
+		{routingPath.Child("consoleHostname"), r.Spec.Routing.ConsoleHostname},
+	} {
+		if hostname.value == "" {
+			continue
+		}
+		for _, msg := range validation.IsDNS1123Subdomain(hostname.value) {
+			allErrs = append(allErrs, field.Invalid(hostname.path, hostname.value, msg))
+		}
+	}
+
+	return allErrs
+}
+
+// validateImmutableFields rejects changes of the hostnames, which are bound to the routes and
+// certificates of the namespace, and of the database identity, which Account IAM cannot migrate
+func (r *AccountIAM) validateImmutableFields(old *AccountIAM) field.ErrorList {
+	var allErrs field.ErrorList
+
+	oldSpec := old.Spec.DeepCopy()
+	oldSpec.SetDefaults()
+	newSpec := r.Spec.DeepCopy()
+	newSpec.SetDefaults()
+
+	routingPath := field.NewPath("spec", "routing")
+	databasePath := field.NewPath("spec", "database")
+	for _, immutable := range []struct {
+		path     *field.Path
+		oldValue string
+		newValue string
+	}{
+		{routingPath.Child("apiHostname"), oldSpec.Routing.APIHostname, newSpec.Routing.APIHostname},
+		{routingPath.Child("consoleHostname"), oldSpec.Routing.ConsoleHostname, newSpec.Routing.ConsoleHostname},
+		{databasePath.Child("name"), oldSpec.Database.Name, newSpec.Database.Name},
+		{databasePath.Child("schema"), oldSpec.Database.Schema, newSpec.Database.Schema},
+		{databasePath.Child("user"), oldSpec.Database.User, newSpec.Database.User},
+	} {
+		if immutable.oldValue != immutable.newValue {
+			allErrs = append(allErrs, field.Forbidden(immutable.path,
+				fmt.Sprintf("is immutable, cannot change from %q to %q", immutable.oldValue, immutable.newValue)))
+		}
+	}
+	return allErrs
+}
+
+// validateDatabaseObjects rejects a database name, schema or user other than the defaults unless the
+// database is pre-provisioned, since the bootstrap job only creates the default ones
+func (r *AccountIAM) validateDatabaseObjects() field.ErrorList {
+	database := r.Spec.Database
+	if database.PreProvisioned {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	databasePath := field.NewPath("spec", "database")
+	for _, object := range []struct {
+		path         *field.Path
+		value        string
+		defaultValue string
+	}{
+		{databasePath.Child("name"), database.Name, DefaultDatabaseName},
+		{databasePath.Child("schema"), database.Schema, DefaultDatabaseSchema},
+		{databasePath.Child("user"), database.User, DefaultDatabaseUser},
+	} {
+		if object.value != "" && object.value != object.defaultValue {
+			allErrs = append(allErrs, field.Invalid(object.path, object.value,
+				fmt.Sprintf("must be %s unless preProvisioned is set, the bootstrap job only creates the default database objects", object.defaultValue)))
+		}
+	}
+	return allErrs
+}
+
+// ignoredFieldWarnings warns about the settings that have no effect with the rest of the spec.
+// There are no deprecated fields in v1alpha1 yet.
+func (r *AccountIAM) ignoredFieldWarnings() admission.Warnings {
+	var warnings admission.Warnings
+
+	redis := r.Spec.Redis
+	if redis.Mode != "" && redis.Mode != RedisModeManaged {
+		if redis.Size != 0 || redis.Version != "" || redis.ScaleConfig != "" || redis.Storage != nil {
+			warnings = append(warnings, fmt.Sprintf(
+				"spec.redis.size, version, scaleConfig and storage are ignored when spec.redis.mode is %s", redis.Mode))
+		}
+	}
+	if redis.External != nil && redis.Mode != RedisModeExternal {
+		warnings = append(warnings, "spec.redis.external is ignored unless spec.redis.mode is External")
+	}
+	return warnings
+}
+
+// toInvalidError turns the validation errors into the Invalid status error of the AccountIAM
+func (r *AccountIAM) toInvalidError(allErrs field.ErrorList) error {
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(schema.GroupKind{Group: GroupVersion.Group, Kind: "AccountIAM"}, r.Name, allErrs)
+}
+
+func validatePort(fldPath *field.Path, port int32) field.ErrorList {
+	if port != 0 && (port < 1 || port > 65535) {
+		return field.ErrorList{field.Invalid(fldPath, port, "must be between 1 and 65535")}
+	}
+	return nil
+}
+
+func validateReplicas(fldPath *field.Path, replicas *int32) field.ErrorList {
+	if replicas != nil && *replicas < 1 {
+		return field.ErrorList{field.Invalid(fldPath, *replicas, "must be at least 1")}
+	}
+	return nil
+}
+
+// validateResources checks that the quantities are not negative and that no request exceeds its limit
+func validateResources(fldPath *field.Path, resources *corev1.ResourceRequirements) field.ErrorList {
+	if resources == nil {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	for name, quantity := range resources.Requests {
+		if quantity.Sign() < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("requests").Key(string(name)), quantity.String(), "must not be negative"))
+		}
+	}
+	for name, quantity := range resources.Limits {
+		if quantity.Sign() < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("limits").Key(string(name)), quantity.String(), "must not be negative"))
+		}
+		if request, ok := resources.Requests[name]; ok && request.Cmp(quantity) > 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("requests").Key(string(name)), request.String(),
+				fmt.Sprintf("must be less than or equal to the %s limit %s", name, quantity.String())))
+		}
+	}
+	return allErrs
+}
+
+// validateSecretKeySelector checks that a secret key reference names a valid secret and key
+func validateSecretKeySelector(fldPath *field.Path, selector *corev1.SecretKeySelector) field.ErrorList {
+	if selector == nil {
+		return nil
+	}
+
+	allErrs := validateSecretName(fldPath.Child("name"), selector.Name)
+	if selector.Key == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("key"), ""))
+	} else {
+		for _, msg := range validation.IsConfigMapKey(selector.Key) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("key"), selector.Key, msg))
+		}
+	}
+	return allErrs
+}
+
+func validateSecretName(fldPath *field.Path, name string) field.ErrorList {
+	if name == "" {
+		return field.ErrorList{field.Required(fldPath, "")}
+	}
+
+	var allErrs field.ErrorList
+	for _, msg := range validation.IsDNS1123Subdomain(name) {
+		allErrs = append(allErrs, field.Invalid(fldPath, name, msg))
+	}
+	return allErrs
+}
@@ -18,29 +18,118 @@ package v1alpha1
 
 import (
 	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/IBM/ibm-user-management-operator/version"
 )
 
 var _ = Describe("AccountIAM Webhook", func() {
 
+	newAccountIAM := func(name string) *AccountIAM {
+		return &AccountIAM{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+		}
+	}
+
 	Context("When creating AccountIAM under Defaulting Webhook", func() {
 		It("Should fill in the default value if a required field is empty", func() {
-
-			// TODO(user): Add your logic here
-
+			accountIAM := newAccountIAM("defaulted-accountiam")
+			accountIAM.Spec.Database.Name = "custom_database"
+			accountIAM.Spec.Database.PreProvisioned = true
+			Expect(k8sClient.Create(ctx, accountIAM)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, newAccountIAM("defaulted-accountiam"))
+
+			spec := accountIAM.Spec
+			Expect(spec.Redis.Mode).To(Equal(RedisModeManaged))
+			Expect(spec.Redis.Size).To(Equal(DefaultRedisSize))
+			Expect(spec.Redis.Version).To(Equal(DefaultRedisVersion))
+			Expect(spec.Database.Name).To(Equal("custom_database"))
+			Expect(spec.Database.SSLMode).To(Equal(DefaultDatabaseSSLMode))
+			Expect(*spec.AccountIAM.Replicas).To(Equal(DefaultAccountIAMReplicas))
+			Expect(spec.AccountIAM.Resources.Limits.Memory().String()).To(Equal("800Mi"))
+			Expect(spec.AccountIAM.SubscriptionName).To(Equal(DefaultSubscriptionName))
+			Expect(spec.UI.Resources).NotTo(BeNil())
+			Expect(spec.Routing.APIHostname).To(BeEmpty())
+			Expect(accountIAM.Annotations).To(HaveKeyWithValue(DefaultsVersionAnnotation, version.Version))
 		})
 	})
 
 	Context("When creating AccountIAM under Validating Webhook", func() {
 		It("Should deny if a required field is empty", func() {
+			accountIAM := newAccountIAM("missing-external-redis")
+			accountIAM.Spec.Redis.Mode = RedisModeExternal
 
-			// TODO(user): Add your logic here
+			err := k8sClient.Create(ctx, accountIAM)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.redis.external"))
+		})
 
+		It("Should deny malformed secret references and out of range values", func() {
+			accountIAM := newAccountIAM("malformed-spec")
+			accountIAM.Spec.Database.PasswordSecretRef = &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "Not_A_Secret"},
+			}
+			accountIAM.Spec.UI.Resources = &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+				Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+			}
+
+			err := k8sClient.Create(ctx, accountIAM)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.database.passwordSecretRef.name"))
+			Expect(err.Error()).To(ContainSubstring("spec.database.passwordSecretRef.key"))
+			Expect(err.Error()).To(ContainSubstring("spec.ui.resources.requests[memory]"))
 		})
 
 		It("Should admit if all required fields are provided", func() {
+			accountIAM := newAccountIAM("valid-accountiam")
+			accountIAM.Spec.Redis.Mode = RedisModeExternal
+			accountIAM.Spec.Redis.External = &ExternalRedisSpec{
+				Host: "redis.example.com",
+				PasswordSecretRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "redis-credentials"},
+					Key:                  "password",
+				},
+			}
+			Expect(k8sClient.Create(ctx, accountIAM)).To(Succeed())
+
+			By("denying a second AccountIAM in the namespace")
+			err := k8sClient.Create(ctx, newAccountIAM("second-accountiam"))
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("only one AccountIAM is allowed per namespace"))
+
+			By("denying a change of the database identity")
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: accountIAM.Name, Namespace: accountIAM.Namespace}, accountIAM)).To(Succeed())
+			accountIAM.Spec.Database.Name = "another_database"
+			err = k8sClient.Update(ctx, accountIAM)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.database.name"))
+
+			Expect(k8sClient.Delete(ctx, newAccountIAM("valid-accountiam"))).To(Succeed())
+		})
+
+		It("Should deny a custom database the bootstrap job cannot create", func() {
+			accountIAM := newAccountIAM("custom-database")
+			accountIAM.Spec.Database.Name = "custom_database"
+			accountIAM.Spec.Database.User = DefaultDatabaseUser
 
-			// TODO(user): Add your logic here
+			err := k8sClient.Create(ctx, accountIAM)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.database.name"))
+			Expect(err.Error()).NotTo(ContainSubstring("spec.database.user"))
 
+			By("admitting it once pre-provisioned")
+			accountIAM.Spec.Database.PreProvisioned = true
+			Expect(k8sClient.Create(ctx, accountIAM)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, accountIAM)).To(Succeed())
 		})
 	})
 
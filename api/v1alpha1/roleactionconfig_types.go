@@ -30,9 +30,71 @@ type RoleActionConfigSpec struct {
 
 	ServiceID string `json:"serviceID"`
 
+	// DisplayName is the name of the product shown by Account IAM
+	// +optional
+	DisplayName string `json:"displayName,omitempty"`
+
+	// Description describes the product in Account IAM
+	// +optional
+	Description string `json:"description,omitempty"`
+
 	IAM IAM `json:"IAM,omitempty"`
+
+	// AccountIAMRef selects the AccountIAM the product is registered in. When it is not set,
+	// the AccountIAM is looked up in the namespace of the operator.ibm.com/account-iam-ns label,
+	// or is the only AccountIAM of the cluster.
+	// +optional
+	AccountIAMRef *AccountIAMReference `json:"accountIAMRef,omitempty"`
+
+	// DeletionPolicy controls whether the custom roles and actions of the product
+	// are removed from Account IAM when the RoleActionConfig is deleted
+	// +optional
+	// +kubebuilder:default=Delete
+	// +kubebuilder:validation:Enum=Delete;Orphan
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
+
+	// SyncMode controls whether the changes to the product are applied to Account IAM. In Plan mode,
+	// the requests a sync would send are reported in status.plannedOperations instead, and nothing
+	// is removed from Account IAM when the RoleActionConfig is deleted.
+	// +optional
+	// +kubebuilder:default=Apply
+	// +kubebuilder:validation:Enum=Apply;Plan
+	SyncMode string `json:"syncMode,omitempty"`
+}
+
+// AccountIAMReference identifies an AccountIAM instance
+type AccountIAMReference struct {
+	// Name is the name of the AccountIAM
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the AccountIAM. It defaults to the namespace of the RoleActionConfig
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
 }
 
+// AccountIAMNamespaceLabel selects the namespace of the AccountIAM a RoleActionConfig registers its product in
+const AccountIAMNamespaceLabel = "operator.ibm.com/account-iam-ns"
+
+// AcknowledgePruningAnnotation lets a sync blocked by the safeguard on action deletions prune the actions.
+// Its value is the generation of the RoleActionConfig acknowledged, so it does not outlive the spec it was set for.
+const AcknowledgePruningAnnotation = "operator.ibm.com/acknowledge-pruning"
+
+// Deletion policies of RoleActionConfig
+const (
+	// DeletionPolicyDelete removes the custom roles and actions from Account IAM
+	DeletionPolicyDelete = "Delete"
+	// DeletionPolicyOrphan leaves the custom roles and actions in Account IAM
+	DeletionPolicyOrphan = "Orphan"
+)
+
+// Sync modes of RoleActionConfig
+const (
+	// SyncModeApply applies the changes to the product to Account IAM
+	SyncModeApply = "Apply"
+	// SyncModePlan only reports the changes to the product, without sending any mutating request to Account IAM
+	SyncModePlan = "Plan"
+)
+
 type IAM struct {
 	// +optional
 	V2 bool `json:"v2"`
@@ -43,24 +105,137 @@ type IAM struct {
 	// +optional
 	// +kubebuilder:validation:MaxItems=100
 	Actions []string `json:"actions,omitempty"`
+	// ActionDescriptions describe the product level actions in Account IAM, by action name
+	// +optional
+	ActionDescriptions map[string]string `json:"actionDescriptions,omitempty"`
 }
 
 type V2CustomRoles struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	// DisplayName is the name of the custom role shown by Account IAM
+	// +optional
+	DisplayName string `json:"displayName,omitempty"`
+	// BindableLevel is the level the custom role can be bound at in Account IAM
+	// +optional
+	// +kubebuilder:default=SERVICE
+	BindableLevel string `json:"bindableLevel,omitempty"`
 	// +optional
 	// +kubebuilder:validation:MaxItems=100
 	Actions []string `json:"actions,omitempty"`
 }
 
+// BindableLevelService binds a custom role at the level of the service, the default of the custom roles
+const BindableLevelService = "SERVICE"
+
+// ConditionTypeBlocked reports the pruning of actions held back by the safeguard on action deletions
+const ConditionTypeBlocked = "Blocked"
+
+// Condition reasons of RoleActionConfig
+const (
+	ConditionReasonSynced        = "Synced"
+	ConditionReasonSyncFailed    = "SyncFailed"
+	ConditionReasonCleanupFailed = "CleanupFailed"
+	ConditionReasonPlanned       = "Planned"
+	ConditionReasonPruneBlocked  = "PruneBlocked"
+)
+
+// RoleStatus reports a custom role as registered in Account IAM
+type RoleStatus struct {
+	// Name is the name of the custom role
+	Name string `json:"name"`
+
+	// UID is the UID of the custom role in Account IAM
+	// +optional
+	UID string `json:"uid,omitempty"`
+
+	// Actions are the actions of the custom role in Account IAM
+	// +optional
+	Actions []string `json:"actions,omitempty"`
+
+	// Outcome is how the last sync changed the custom role: Created, Updated, Unchanged or Failed
+	// +optional
+	Outcome string `json:"outcome,omitempty"`
+}
+
+// Outcomes of the last sync of a custom role
+const (
+	RoleOutcomeCreated   = "Created"
+	RoleOutcomeUpdated   = "Updated"
+	RoleOutcomeUnchanged = "Unchanged"
+	RoleOutcomeDeleted   = "Deleted"
+	RoleOutcomeFailed    = "Failed"
+)
+
+// PlannedOperation reports a change a sync in Plan mode would make in Account IAM
+type PlannedOperation struct {
+	// Type is the kind of change, such as CreateRole or RemoveProductAction
+	Type string `json:"type"`
+
+	// Request is the method and path of the request sending the change to Account IAM.
+	// The UID of a custom role yet to be created is written as {role name}.
+	Request string `json:"request"`
+
+	// Role is the name of the custom role the change applies to
+	// +optional
+	Role string `json:"role,omitempty"`
+
+	// Action is the action added or removed
+	// +optional
+	Action string `json:"action,omitempty"`
+
+	// Blocked tells whether the change is pruning held back until it is acknowledged
+	// +optional
+	Blocked bool `json:"blocked,omitempty"`
+}
+
 // RoleActionConfigStatus defines the observed state of RoleActionConfig
 type RoleActionConfigStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	// AccountIAM is the AccountIAM instance the product is bound to
+	// +optional
+	AccountIAM *AccountIAMReference `json:"accountIAM,omitempty"`
+
+	// ProductRegistered reports whether the product is registered in Account IAM
+	// +optional
+	ProductRegistered bool `json:"productRegistered,omitempty"`
+
+	// Roles are the custom roles of the product in Account IAM
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Roles []RoleStatus `json:"roles,omitempty"`
+
+	// Actions are the product level actions in Account IAM
+	// +optional
+	Actions []string `json:"actions,omitempty"`
+
+	// PlannedOperations are the changes the last sync in Plan mode would make in Account IAM, in order.
+	// They are cleared when the RoleActionConfig is synced in Apply mode.
+	// +optional
+	PlannedOperations []PlannedOperation `json:"plannedOperations,omitempty"`
+
+	// LastSyncTime is the last time the product was synced with Account IAM without errors
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// ObservedGeneration is the generation of the spec last synced with Account IAM
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions report whether the product is in sync with Account IAM.
+	// The Ready condition carries the last Account IAM error.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Service ID",type="string",JSONPath=".spec.serviceID"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="Last Sync",type="date",JSONPath=".status.lastSyncTime"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // RoleActionConfig is the Schema for the roleactionconfigs API
 type RoleActionConfig struct {
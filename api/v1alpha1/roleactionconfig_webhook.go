@@ -0,0 +1,177 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// log is for logging in this package.
+var roleactionconfiglog = logf.Log.WithName("roleactionconfig-resource")
+
+// serviceIDRegexp matches the service IDs usable in the Account IAM product paths
+// and as the prefix of the role actions
+var serviceIDRegexp = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// SetupWebhookWithManager will setup the manager to manage the webhooks
+func (r *RoleActionConfig) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	accountiamReader = mgr.GetAPIReader()
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/mutate-operator-ibm-com-v1alpha1-roleactionconfig,mutating=true,failurePolicy=fail,sideEffects=None,groups=operator.ibm.com,resources=roleactionconfigs,verbs=create;update,versions=v1alpha1,name=mroleactionconfig.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Defaulter = &RoleActionConfig{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type.
+// It binds the RoleActionConfig without spec.accountIAMRef to the namespace of the AccountIAM when there is only one.
+func (r *RoleActionConfig) Default() {
+	roleactionconfiglog.Info("default", "name", r.Name)
+
+	if _, ok := r.Labels[AccountIAMNamespaceLabel]; ok || r.Spec.AccountIAMRef != nil || accountiamReader == nil {
+		return
+	}
+
+	accountIAMs := &AccountIAMList{}
+	if err := accountiamReader.List(context.TODO(), accountIAMs); err != nil {
+		roleactionconfiglog.Error(err, "failed to list AccountIAMs, skipping the namespace label", "name", r.Name)
+		return
+	}
+	if len(accountIAMs.Items) != 1 {
+		return
+	}
+
+	labels := r.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[AccountIAMNamespaceLabel] = accountIAMs.Items[0].Namespace
+	r.SetLabels(labels)
+}
+
+//+kubebuilder:webhook:path=/validate-operator-ibm-com-v1alpha1-roleactionconfig,mutating=false,failurePolicy=fail,sideEffects=None,groups=operator.ibm.com,resources=roleactionconfigs,verbs=create;update,versions=v1alpha1,name=vroleactionconfig.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &RoleActionConfig{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *RoleActionConfig) ValidateCreate() (admission.Warnings, error) {
+	roleactionconfiglog.Info("validate create", "name", r.Name)
+	return nil, r.toInvalidError(r.validateSpec())
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *RoleActionConfig) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	roleactionconfiglog.Info("validate update", "name", r.Name)
+
+	// Let the finalizer be released whatever the spec holds
+	if r.DeletionTimestamp != nil {
+		return nil, nil
+	}
+	return nil, r.toInvalidError(r.validateSpec())
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *RoleActionConfig) ValidateDelete() (admission.Warnings, error) {
+	roleactionconfiglog.Info("validate delete", "name", r.Name)
+	return nil, nil
+}
+
+// validateSpec rejects the specs Account IAM would refuse, before any request is sent to it
+func (r *RoleActionConfig) validateSpec() field.ErrorList {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+	serviceID := r.Spec.ServiceID
+
+	if !serviceIDRegexp.MatchString(serviceID) {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("serviceID"), serviceID,
+			"must start with an alphanumeric character and only contain alphanumeric characters, '-' or '_'"))
+	}
+
+	actionsPath := specPath.Child("IAM", "actions")
+	productActions := sets.New[string]()
+	for i, action := range r.Spec.IAM.Actions {
+		allErrs = append(allErrs, validateAction(actionsPath.Index(i), action, serviceID, productActions)...)
+		productActions.Insert(action)
+	}
+
+	descriptionsPath := specPath.Child("IAM", "actionDescriptions")
+	for _, action := range sets.List(sets.KeySet(r.Spec.IAM.ActionDescriptions)) {
+		if !productActions.Has(action) {
+			allErrs = append(allErrs, field.Invalid(descriptionsPath.Key(action), action, "must be declared in spec.IAM.actions"))
+		}
+	}
+
+	rolesPath := specPath.Child("IAM", "v2CustomRoles")
+	roleNames := sets.New[string]()
+	for i, role := range r.Spec.IAM.V2CustomRoles {
+		rolePath := rolesPath.Index(i)
+		if roleNames.Has(role.Name) {
+			allErrs = append(allErrs, field.Duplicate(rolePath.Child("name"), role.Name))
+		}
+		roleNames.Insert(role.Name)
+
+		roleActions := sets.New[string]()
+		for j, action := range role.Actions {
+			actionPath := rolePath.Child("actions").Index(j)
+			allErrs = append(allErrs, validateAction(actionPath, action, serviceID, roleActions)...)
+			roleActions.Insert(action)
+			if !productActions.Has(action) {
+				allErrs = append(allErrs, field.Invalid(actionPath, action, "must be declared in spec.IAM.actions"))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// validateAction rejects empty, duplicate and already prefixed action names.
+// The operator prefixes the role actions with the service ID itself.
+func validateAction(fldPath *field.Path, action, serviceID string, seen sets.Set[string]) field.ErrorList {
+	switch {
+	case action == "":
+		return field.ErrorList{field.Required(fldPath, "")}
+	case seen.Has(action):
+		return field.ErrorList{field.Duplicate(fldPath, action)}
+	case serviceID != "" && strings.HasPrefix(action, serviceID+"."):
+		return field.ErrorList{field.Invalid(fldPath, action,
+			fmt.Sprintf("must not carry the %q prefix, it is added by the operator", serviceID+"."))}
+	}
+	return nil
+}
+
+// toInvalidError turns the validation errors into the Invalid status error of the RoleActionConfig
+func (r *RoleActionConfig) toInvalidError(allErrs field.ErrorList) error {
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(schema.GroupKind{Group: GroupVersion.Group, Kind: "RoleActionConfig"}, r.Name, allErrs)
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("RoleActionConfig Webhook", func() {
+
+	newRoleActionConfig := func(name string, spec RoleActionConfigSpec) *RoleActionConfig {
+		return &RoleActionConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: spec,
+		}
+	}
+
+	Context("When creating RoleActionConfig under Defaulting Webhook", func() {
+		It("Should label the namespace of the only AccountIAM", func() {
+			accountIAM := &AccountIAM{
+				ObjectMeta: metav1.ObjectMeta{Name: "single-accountiam", Namespace: "default"},
+			}
+			Expect(k8sClient.Create(ctx, accountIAM)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, accountIAM)
+
+			roleActionConfig := newRoleActionConfig("labelled-product", RoleActionConfigSpec{ServiceID: "labelled-product"})
+			Expect(k8sClient.Create(ctx, roleActionConfig)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, roleActionConfig)
+
+			Expect(roleActionConfig.Labels).To(HaveKeyWithValue(AccountIAMNamespaceLabel, "default"))
+		})
+	})
+
+	Context("When creating RoleActionConfig under Validating Webhook", func() {
+		It("Should deny invalid service IDs, roles and actions", func() {
+			roleActionConfig := newRoleActionConfig("invalid-product", RoleActionConfigSpec{
+				ServiceID: "invalid.product",
+				IAM: IAM{
+					Actions:            []string{"read", "read", "invalid.product.write"},
+					ActionDescriptions: map[string]string{"read": "Read", "undeclared": "Undeclared"},
+					V2CustomRoles: []V2CustomRoles{
+						{Name: "viewer", Description: "Viewer", Actions: []string{"read"}},
+						{Name: "viewer", Description: "Editor", Actions: []string{"delete"}},
+					},
+				},
+			})
+
+			err := k8sClient.Create(ctx, roleActionConfig)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.serviceID"))
+			Expect(err.Error()).To(ContainSubstring(`spec.IAM.actions[1]: Duplicate value: "read"`))
+			Expect(err.Error()).To(ContainSubstring("spec.IAM.actions[2]"))
+			Expect(err.Error()).To(ContainSubstring(`spec.IAM.v2CustomRoles[1].name: Duplicate value: "viewer"`))
+			Expect(err.Error()).To(ContainSubstring("spec.IAM.v2CustomRoles[1].actions[0]"))
+			Expect(err.Error()).To(ContainSubstring("spec.IAM.actionDescriptions[undeclared]"))
+		})
+
+		It("Should admit if all required fields are provided", func() {
+			roleActionConfig := newRoleActionConfig("valid-product", RoleActionConfigSpec{
+				ServiceID:   "valid-product",
+				DisplayName: "Valid Product",
+				Description: "A product with all its metadata",
+				IAM: IAM{
+					Actions:            []string{"read", "write"},
+					ActionDescriptions: map[string]string{"read": "Read the resources"},
+					V2CustomRoles: []V2CustomRoles{
+						{Name: "viewer", Description: "Viewer", DisplayName: "Viewer", BindableLevel: BindableLevelService, Actions: []string{"read"}},
+						{Name: "editor", Description: "Editor", Actions: []string{"read", "write"}},
+					},
+				},
+			})
+
+			Expect(k8sClient.Create(ctx, roleActionConfig)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, roleActionConfig)).To(Succeed())
+		})
+	})
+
+})
@@ -21,7 +21,9 @@ limitations under the License.
 package v1alpha1
 
 import (
-	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -29,8 +31,8 @@ func (in *AccountIAM) DeepCopyInto(out *AccountIAM) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountIAM.
@@ -83,9 +85,56 @@ func (in *AccountIAMList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccountIAMReference) DeepCopyInto(out *AccountIAMReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountIAMReference.
+func (in *AccountIAMReference) DeepCopy() *AccountIAMReference {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountIAMReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccountIAMServiceSpec) DeepCopyInto(out *AccountIAMServiceSpec) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountIAMServiceSpec.
+func (in *AccountIAMServiceSpec) DeepCopy() *AccountIAMServiceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountIAMServiceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AccountIAMSpec) DeepCopyInto(out *AccountIAMSpec) {
 	*out = *in
+	in.Redis.DeepCopyInto(&out.Redis)
+	in.Database.DeepCopyInto(&out.Database)
+	in.AccountIAM.DeepCopyInto(&out.AccountIAM)
+	in.UI.DeepCopyInto(&out.UI)
+	out.Routing = in.Routing
+	out.Integration = in.Integration
+	out.Uninstall = in.Uninstall
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountIAMSpec.
@@ -101,6 +150,15 @@ func (in *AccountIAMSpec) DeepCopy() *AccountIAMSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AccountIAMStatus) DeepCopyInto(out *AccountIAMStatus) {
 	*out = *in
+	in.Service.DeepCopyInto(&out.Service)
+	out.Redis = in.Redis
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountIAMStatus.
@@ -113,6 +171,61 @@ func (in *AccountIAMStatus) DeepCopy() *AccountIAMStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseSpec) DeepCopyInto(out *DatabaseSpec) {
+	*out = *in
+	if in.CASecretRef != nil {
+		in, out := &in.CASecretRef, &out.CASecretRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PasswordSecretRef != nil {
+		in, out := &in.PasswordSecretRef, &out.PasswordSecretRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SuperuserSecretRef != nil {
+		in, out := &in.SuperuserSecretRef, &out.SuperuserSecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseSpec.
+func (in *DatabaseSpec) DeepCopy() *DatabaseSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalRedisSpec) DeepCopyInto(out *ExternalRedisSpec) {
+	*out = *in
+	if in.PasswordSecretRef != nil {
+		in, out := &in.PasswordSecretRef, &out.PasswordSecretRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CASecretRef != nil {
+		in, out := &in.CASecretRef, &out.CASecretRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalRedisSpec.
+func (in *ExternalRedisSpec) DeepCopy() *ExternalRedisSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalRedisSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IAM) DeepCopyInto(out *IAM) {
 	*out = *in
@@ -128,6 +241,13 @@ func (in *IAM) DeepCopyInto(out *IAM) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ActionDescriptions != nil {
+		in, out := &in.ActionDescriptions, &out.ActionDescriptions
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IAM.
@@ -140,13 +260,103 @@ func (in *IAM) DeepCopy() *IAM {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntegrationSpec) DeepCopyInto(out *IntegrationSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationSpec.
+func (in *IntegrationSpec) DeepCopy() *IntegrationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IntegrationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlannedOperation) DeepCopyInto(out *PlannedOperation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlannedOperation.
+func (in *PlannedOperation) DeepCopy() *PlannedOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(PlannedOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisSpec) DeepCopyInto(out *RedisSpec) {
+	*out = *in
+	if in.External != nil {
+		in, out := &in.External, &out.External
+		*out = new(ExternalRedisSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(RedisStorageSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisSpec.
+func (in *RedisSpec) DeepCopy() *RedisSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisStatus) DeepCopyInto(out *RedisStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisStatus.
+func (in *RedisStatus) DeepCopy() *RedisStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisStorageSpec) DeepCopyInto(out *RedisStorageSpec) {
+	*out = *in
+	if in.Size != nil {
+		in, out := &in.Size, &out.Size
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisStorageSpec.
+func (in *RedisStorageSpec) DeepCopy() *RedisStorageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisStorageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RoleActionConfig) DeepCopyInto(out *RoleActionConfig) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleActionConfig.
@@ -203,6 +413,11 @@ func (in *RoleActionConfigList) DeepCopyObject() runtime.Object {
 func (in *RoleActionConfigSpec) DeepCopyInto(out *RoleActionConfigSpec) {
 	*out = *in
 	in.IAM.DeepCopyInto(&out.IAM)
+	if in.AccountIAMRef != nil {
+		in, out := &in.AccountIAMRef, &out.AccountIAMRef
+		*out = new(AccountIAMReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleActionConfigSpec.
@@ -218,6 +433,39 @@ func (in *RoleActionConfigSpec) DeepCopy() *RoleActionConfigSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RoleActionConfigStatus) DeepCopyInto(out *RoleActionConfigStatus) {
 	*out = *in
+	if in.AccountIAM != nil {
+		in, out := &in.AccountIAM, &out.AccountIAM
+		*out = new(AccountIAMReference)
+		**out = **in
+	}
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]RoleStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Actions != nil {
+		in, out := &in.Actions, &out.Actions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PlannedOperations != nil {
+		in, out := &in.PlannedOperations, &out.PlannedOperations
+		*out = make([]PlannedOperation, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleActionConfigStatus.
@@ -230,6 +478,81 @@ func (in *RoleActionConfigStatus) DeepCopy() *RoleActionConfigStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RoleStatus) DeepCopyInto(out *RoleStatus) {
+	*out = *in
+	if in.Actions != nil {
+		in, out := &in.Actions, &out.Actions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleStatus.
+func (in *RoleStatus) DeepCopy() *RoleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RoleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RoutingSpec) DeepCopyInto(out *RoutingSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoutingSpec.
+func (in *RoutingSpec) DeepCopy() *RoutingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RoutingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UISpec) DeepCopyInto(out *UISpec) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UISpec.
+func (in *UISpec) DeepCopy() *UISpec {
+	if in == nil {
+		return nil
+	}
+	out := new(UISpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UninstallSpec) DeepCopyInto(out *UninstallSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UninstallSpec.
+func (in *UninstallSpec) DeepCopy() *UninstallSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(UninstallSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *V2CustomRoles) DeepCopyInto(out *V2CustomRoles) {
 	*out = *in
@@ -2,12 +2,16 @@ package account_iam
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
-	"net/url"
+	"strings"
+	"sync"
+	"time"
 
 	operatorv1alpha1 "github.com/IBM/ibm-user-management-operator/api/v1alpha1"
 	"github.com/IBM/ibm-user-management-operator/internal/retry"
@@ -15,41 +19,33 @@ import (
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
-type ProductCustomRoles struct {
-	Resources []Resources `json:"resources"`
-}
-
-type ActionDefinition struct {
-	Resources []ActionResources `json:"resources"`
-}
-type ActionResources struct {
-	Name string `json:"name"`
-}
-
-type Resources struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	UID         string `json:"uid"`
+// ProductMetadata describes a product registered in Account IAM
+type ProductMetadata struct {
+	DisplayName string `json:"displayName,omitempty"`
+	Description string `json:"description,omitempty"`
 }
 
 type IAMClient interface {
-	Get(url string) (*http.Response, int, error)
-	Post(url string, body *bytes.Reader) (*http.Response, int, error)
-	Patch(url string, body *bytes.Reader) (*http.Response, int, error)
-	Delete(url string) (*http.Response, int, error)
-	GetToken(url string) (string, error)
-	GetUID(instance *operatorv1alpha1.RoleActionConfig) (map[string]string, int, error)
-	GetProductDetails(serviceID string) (map[string]any, int, error)
-	PostNewProduct(serviceID string) ([]byte, int, error)
-	PostCustomRoles(v2CustomRole operatorv1alpha1.V2CustomRoles, serviceID string) ([]byte, int, error)
-	UpdateCustomRoles(v2CustomRole operatorv1alpha1.V2CustomRoles, serviceID string, UID string) ([]byte, int, error)
-	DeleteCustomRoles(instance *operatorv1alpha1.RoleActionConfig, UID string) ([]byte, int, error)
-	PostActionsProductLevel(action string, serviceID string) ([]byte, int, error)
-	GetActionsProductLevel(serviceID string) ([]map[string]string, int, error)
-	DeleteActionsProductLevel(serviceID string, actionName string) ([]byte, int, error)
-	GetActionsRoleLevel(serviceID string, roleUID string) ([]map[string]string, int, error)
-	PostActionsRoleLevel(action string, roleUID string, serviceID string) ([]byte, int, error)
-	DeleteActionsRoleLevel(serviceID string, roleUID string, actionName string) ([]byte, int, error)
+	Get(ctx context.Context, url string) (*http.Response, int, error)
+	Post(ctx context.Context, url string, body *bytes.Reader) (*http.Response, int, error)
+	Patch(ctx context.Context, url string, body *bytes.Reader) (*http.Response, int, error)
+	Delete(ctx context.Context, url string) (*http.Response, int, error)
+	GetToken(ctx context.Context, url string) (string, error)
+	GetUID(ctx context.Context, instance *operatorv1alpha1.RoleActionConfig) (map[string]string, int, error)
+	GetRoles(ctx context.Context, serviceID string) ([]Role, int, error)
+	GetProductDetails(ctx context.Context, serviceID string) (*Product, int, error)
+	PostNewProduct(ctx context.Context, serviceID string, metadata ProductMetadata) ([]byte, int, error)
+	UpdateProduct(ctx context.Context, serviceID string, metadata ProductMetadata) ([]byte, int, error)
+	PostCustomRoles(ctx context.Context, v2CustomRole operatorv1alpha1.V2CustomRoles, serviceID string) (*Role, int, error)
+	UpdateCustomRoles(ctx context.Context, v2CustomRole operatorv1alpha1.V2CustomRoles, serviceID string, UID string) ([]byte, int, error)
+	DeleteCustomRoles(ctx context.Context, instance *operatorv1alpha1.RoleActionConfig, UID string) ([]byte, int, error)
+	PostActionsProductLevel(ctx context.Context, action string, description string, serviceID string) ([]byte, int, error)
+	UpdateActionProductLevel(ctx context.Context, serviceID string, actionName string, description string) ([]byte, int, error)
+	GetActionsProductLevel(ctx context.Context, serviceID string) ([]Action, int, error)
+	DeleteActionsProductLevel(ctx context.Context, serviceID string, actionName string) ([]byte, int, error)
+	GetActionsRoleLevel(ctx context.Context, serviceID string, roleUID string) ([]Action, int, error)
+	PostActionsRoleLevel(ctx context.Context, action string, roleUID string, serviceID string) ([]byte, int, error)
+	DeleteActionsRoleLevel(ctx context.Context, serviceID string, roleUID string, actionName string) ([]byte, int, error)
 }
 
 type MCSPIAMClient struct {
@@ -58,6 +54,15 @@ type MCSPIAMClient struct {
 	ApiKey     string
 	HTTPClient *http.Client
 	retry      *retry.Retry
+
+	// APIKeySource re-reads the API key when Account IAM rejects the token, so a rotated key is picked up mid-sync
+	APIKeySource func(ctx context.Context) (string, error)
+
+	// tokenMu guards Token, ApiKey and the token endpoint and expiry, which are refreshed
+	// while other reconciles use the client
+	tokenMu     sync.RWMutex
+	tokenURL    string
+	tokenExpiry time.Time
 }
 
 type apiKeyBody struct {
@@ -69,16 +74,25 @@ type ApiToken struct {
 }
 
 const (
-	tokenUrl    = "api/2.0/accounts/global_account/apikeys/token"
-	pageSize    = "pageSize"
-	maxPageSize = "100" //currently account IAM allows max page size as 100 for API
+	tokenUrl = "api/2.0/accounts/global_account/apikeys/token"
+	// ProductsPath is the path of the products API of Account IAM
+	ProductsPath = "/api/2.0/products"
+	pageSize     = "pageSize"
+	maxPageSize  = "100" //currently account IAM allows max page size as 100 for API
+	// tokenRefreshMargin is how long before its expiry a token is replaced
+	tokenRefreshMargin = time.Minute
+	// defaultTokenLifetime is the lifetime assumed for the tokens without an exp claim
+	defaultTokenLifetime = 10 * time.Minute
 )
 
-func NewMCSPIAMClient(baseUrl string, apiKey string, retryHandler *retry.Retry) (IAMClient, error) {
+// NewMCSPIAMClient returns a client of the Account IAM products API at baseUrl, connecting with tlsConfig.
+// Each request, including the read of its response body, is bounded by requestTimeout when it is positive.
+func NewMCSPIAMClient(baseUrl string, apiKey string, tlsConfig *tls.Config, requestTimeout time.Duration, retryHandler *retry.Retry) (IAMClient, error) {
 	httpClient := &http.Client{
 		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, // TODO: change to trust account-iam's certificate
+			TLSClientConfig: tlsConfig,
 		},
+		Timeout: requestTimeout,
 	}
 
 	return &MCSPIAMClient{
@@ -91,110 +105,146 @@ func NewMCSPIAMClient(baseUrl string, apiKey string, retryHandler *retry.Retry)
 
 var log = logf.Log.WithName("controller_product_registration")
 
-func (c *MCSPIAMClient) Get(url string) (*http.Response, int, error) {
-	status := 0
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		log.Error(err, "failed to create GET request")
-		return nil, status, err
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	var bearer = "Bearer " + c.Token
-	req.Header.Add("Authorization", bearer)
+func (c *MCSPIAMClient) Get(ctx context.Context, url string) (*http.Response, int, error) {
+	return c.do(ctx, "GET", url, nil)
+}
 
-	//added checks for nil response to avoid runtime panics and operator crash
-	res, err := c.HTTPClient.Do(req)
-	if err != nil {
-		if res != nil {
-			status = res.StatusCode
-		}
-		return nil, status, fmt.Errorf("failed to do GET request: %v", err)
-	}
+func (c *MCSPIAMClient) Post(ctx context.Context, url string, body *bytes.Reader) (*http.Response, int, error) {
+	return c.do(ctx, "POST", url, body)
+}
 
-	return res, res.StatusCode, nil
+func (c *MCSPIAMClient) Patch(ctx context.Context, url string, body *bytes.Reader) (*http.Response, int, error) {
+	return c.do(ctx, "PATCH", url, body)
+}
 
+func (c *MCSPIAMClient) Delete(ctx context.Context, url string) (*http.Response, int, error) {
+	return c.do(ctx, "DELETE", url, nil)
 }
 
-func (c *MCSPIAMClient) Post(url string, body *bytes.Reader) (*http.Response, int, error) {
-	status := 0
-	req, err := http.NewRequest("POST", url, body)
-	if err != nil {
-		log.Error(err, "failed to create POST request")
-		return nil, status, err
+// do sends a request to Account IAM. When the token is rejected with a 401, a new token
+// is minted and the request is sent once more.
+func (c *MCSPIAMClient) do(ctx context.Context, method string, url string, body *bytes.Reader) (*http.Response, int, error) {
+	res, token, err := c.send(ctx, method, url, body)
+	if err == nil && res.StatusCode == http.StatusUnauthorized && c.canRefreshToken() {
+		res.Body.Close()
+		logger.Info().Msgf("%s %s returned %d, refreshing the token", method, url, res.StatusCode)
+		if err := c.refreshToken(ctx, token); err != nil {
+			return nil, http.StatusUnauthorized, fmt.Errorf("failed to refresh the token rejected by %s request: %w", method, err)
+		}
+		if body != nil {
+			if _, err := body.Seek(0, io.SeekStart); err != nil {
+				return nil, 0, err
+			}
+		}
+		res, _, err = c.send(ctx, method, url, body)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Add("Authorization", "Bearer "+c.Token)
-
 	//added checks for nil response to avoid runtime panics and operator crash
-	res, err := c.HTTPClient.Do(req)
 	if err != nil {
-		if res != nil {
-			status = res.StatusCode
-		}
-		return nil, status, fmt.Errorf("failed to do POST request: %v", err)
+		return nil, 0, fmt.Errorf("failed to do %s request: %v", method, err)
 	}
-
 	return res, res.StatusCode, nil
 }
 
-func (c *MCSPIAMClient) Patch(url string, body *bytes.Reader) (*http.Response, int, error) {
-	status := 0
-	req, err := http.NewRequest("PATCH", url, body)
+// send sends a request with the current token, which it returns with the response
+func (c *MCSPIAMClient) send(ctx context.Context, method string, url string, body *bytes.Reader) (*http.Response, string, error) {
+	token, err := c.bearerToken(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// a nil *bytes.Reader is not a nil io.Reader
+	var reader io.Reader
+	if body != nil {
+		reader = body
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
 	if err != nil {
-		log.Error(err, "failed to create PATCH request")
-		return nil, status, err
+		log.Error(err, "failed to create "+method+" request")
+		return nil, token, err
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	bearer := "Bearer " + c.Token
-	req.Header.Add("Authorization", bearer)
+	req.Header.Add("Authorization", "Bearer "+token)
 
-	//added checks for nil response to avoid runtime panics and operator crash
 	res, err := c.HTTPClient.Do(req)
-	if err != nil {
-		if res != nil {
-			status = res.StatusCode
-		}
-		return nil, status, fmt.Errorf("failed to do PATCH request: %v", err)
-	}
+	return res, token, err
+}
 
-	return res, res.StatusCode, nil
+// GetToken returns a token of the Account IAM API at url. The token is cached and only
+// minted again when it is about to expire.
+func (c *MCSPIAMClient) GetToken(ctx context.Context, url string) (string, error) {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
 
+	c.tokenURL = url
+	if c.tokenValid() {
+		return c.Token, nil
+	}
+	if err := c.mintToken(ctx); err != nil {
+		return "", err
+	}
+	return c.Token, nil
 }
 
-func (c *MCSPIAMClient) Delete(url string) (*http.Response, int, error) {
-	status := 0
-	req, err := http.NewRequest("DELETE", url, nil)
-	if err != nil {
-		log.Error(err, "failed to create the request")
-		return nil, status, err
+// bearerToken returns the cached token, minting a new one first if it is about to expire
+func (c *MCSPIAMClient) bearerToken(ctx context.Context) (string, error) {
+	c.tokenMu.RLock()
+	token, valid := c.Token, c.tokenURL == "" || c.tokenValid()
+	c.tokenMu.RUnlock()
+	if valid {
+		return token, nil
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+	// another request may have refreshed the token meanwhile
+	if !c.tokenValid() {
+		if err := c.mintToken(ctx); err != nil {
+			return "", err
+		}
+	}
+	return c.Token, nil
+}
 
-	bearer := "Bearer " + c.Token
-	req.Header.Add("Authorization", bearer)
+// canRefreshToken reports whether the token endpoint is known, GetToken not being called before otherwise
+func (c *MCSPIAMClient) canRefreshToken() bool {
+	c.tokenMu.RLock()
+	defer c.tokenMu.RUnlock()
+	return c.tokenURL != ""
+}
 
-	//added checks for nil response to avoid runtime panics and operator crash
-	res, err := c.HTTPClient.Do(req)
-	if err != nil {
-		if res != nil {
-			status = res.StatusCode
+// refreshToken replaces the rejected token with a token minted from the latest API key
+func (c *MCSPIAMClient) refreshToken(ctx context.Context, rejected string) error {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+
+	// another request already replaced the rejected token
+	if c.Token != rejected {
+		return nil
+	}
+	if c.APIKeySource != nil {
+		apiKey, err := c.APIKeySource(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to read the API key: %w", err)
 		}
-		return nil, status, fmt.Errorf("failed to do DELETE request: %v", err)
+		c.ApiKey = apiKey
 	}
+	return c.mintToken(ctx)
+}
 
-	return res, res.StatusCode, nil
-
+// tokenValid reports whether the cached token can still be used. tokenMu must be held.
+func (c *MCSPIAMClient) tokenValid() bool {
+	return c.Token != "" && time.Until(c.tokenExpiry) > tokenRefreshMargin
 }
 
-func (c *MCSPIAMClient) GetToken(url string) (string, error) {
+// mintToken exchanges the API key for a new token. tokenMu must be held for writing.
+func (c *MCSPIAMClient) mintToken(ctx context.Context) error {
+	url := c.tokenURL
 	var finalErr error
 	var tokenBody ApiToken
 	//retryhandler to avoid crashing the operator when IAM is down
-	retryErr := c.retry.RetryHandler(func() error {
+	retryErr := c.retry.RetryHandler(ctx, func() error {
 		jsonApiKey, err := json.Marshal(apiKeyBody{Apikey: c.ApiKey})
 		if err != nil {
 			log.Error(err, "failed json marshalling")
@@ -202,130 +252,116 @@ func (c *MCSPIAMClient) GetToken(url string) (string, error) {
 			return err
 		}
 
-		resp, err := c.HTTPClient.Post(url, "application/json", bytes.NewBuffer(jsonApiKey))
+		req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonApiKey))
+		if err != nil {
+			log.Error(err, "failed to create POST request")
+			finalErr = err
+			return retry.NewPermanentError(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := c.HTTPClient.Do(req)
 		if err != nil {
 			log.Error(err, "failed IAM POST call to "+url)
 			finalErr = err
 			return err
 		}
+		defer resp.Body.Close()
 
-		if resp != nil {
-			defer resp.Body.Close()
-
-			tokenString, err := io.ReadAll(resp.Body)
-			if err != nil {
-				log.Error(err, "Error reading response body")
-				finalErr = err
-				return err
-			}
-
-			err = json.Unmarshal(tokenString, &tokenBody)
-			if err != nil {
-				log.Error(err, "Error unmarshalling token response")
-				finalErr = err
-				return err
-			}
+		if resp.StatusCode >= 400 && resp.StatusCode < 500 {
+			finalErr = fmt.Errorf("POST token returned client error: %d", resp.StatusCode)
+			return retry.NewPermanentError(finalErr)
 		}
-		if resp == nil {
-			logger.Warn().Msg("Response or Body is nil, skipping body read and close")
-			finalErr = fmt.Errorf("nil response or body")
+		if resp.StatusCode >= 500 {
+			finalErr = fmt.Errorf("POST token returned server error: %d", resp.StatusCode)
 			return finalErr
 		}
 
+		tokenString, err := io.ReadAll(resp.Body)
+		if err != nil {
+			log.Error(err, "Error reading response body")
+			finalErr = err
+			return err
+		}
+
+		err = json.Unmarshal(tokenString, &tokenBody)
+		if err != nil {
+			log.Error(err, "Error unmarshalling token response")
+			finalErr = err
+			return err
+		}
 		return nil
 	})
 
 	if retryErr != nil {
 		log.Error(finalErr, "GET Token failed after retries")
-		return "", finalErr
+		return retryErr
 	}
 
 	c.Token = tokenBody.Token
-	return c.Token, nil
-
+	c.tokenExpiry = tokenExpiry(tokenBody.Token)
+	return nil
 }
 
-func (c *MCSPIAMClient) GetUID(instance *operatorv1alpha1.RoleActionConfig) (map[string]string, int, error) {
-	serviceID := instance.Spec.ServiceID
-	customRolesEndpoint := c.BaseURL + "/" + serviceID + "/roles"
-
-	var statusCode int
-	var finalErr error
-	var productCustomRoles ProductCustomRoles
-	var customRole = make(map[string]string)
-
-	retryErr := c.retry.RetryHandler(func() error {
-		// Add pageSize to the query
-		reqURL, err := url.Parse(customRolesEndpoint)
-		if err != nil {
-			log.Error(err, "Failed to parse customRolesEndpoint for GetUID request", customRolesEndpoint)
-			finalErr = fmt.Errorf("failed to parse customRolesEndpoint for GetUID request %v", err)
-			return retry.NewPermanentError(finalErr)
-		}
-
-		query := reqURL.Query()
-		query.Set(pageSize, maxPageSize)
-		reqURL.RawQuery = query.Encode()
-
-		responseCustomRoles, code, err := c.Get(reqURL.String())
-		statusCode = code
-
-		if err != nil {
-			finalErr = fmt.Errorf("failed to do GET request for UIDs: %v", err)
-			return finalErr
-		}
-
-		if statusCode >= 400 && statusCode < 500 {
-			finalErr = fmt.Errorf("GET returned client error: %d", statusCode)
-			return retry.NewPermanentError(finalErr)
-		}
-
-		if statusCode >= 500 {
-			finalErr = fmt.Errorf("failed to do GET request: %d", statusCode)
-			return finalErr
-		}
-
-		if responseCustomRoles != nil && responseCustomRoles.Body != nil {
-			defer responseCustomRoles.Body.Close()
-			if err := json.NewDecoder(responseCustomRoles.Body).Decode(&productCustomRoles); err != nil && err != io.EOF {
-				log.Error(err, "failed to decode response results")
-				finalErr = err
-				return err
+// tokenExpiry returns the expiry of the exp claim of a JWT token, or the default lifetime
+// from now if the token does not carry one. The token is not verified, Account IAM does.
+func tokenExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) == 3 {
+		if payload, err := base64.RawURLEncoding.DecodeString(parts[1]); err == nil {
+			var claims struct {
+				Exp int64 `json:"exp"`
+			}
+			if err := json.Unmarshal(payload, &claims); err == nil && claims.Exp > 0 {
+				return time.Unix(claims.Exp, 0)
 			}
 		}
-		if responseCustomRoles == nil {
-			logger.Warn().Msg("Response or Body is nil, skipping body read and close")
-		}
+	}
+	return time.Now().Add(defaultTokenLifetime)
+}
 
-		logger.Info().Msgf("GET succeeded with status %d", statusCode)
-		return nil
-	})
+func (c *MCSPIAMClient) GetUID(ctx context.Context, instance *operatorv1alpha1.RoleActionConfig) (map[string]string, int, error) {
+	roles, statusCode, err := c.GetRoles(ctx, instance.Spec.ServiceID)
+	if err != nil {
+		return nil, statusCode, err
+	}
 
-	if retryErr != nil {
-		logger.Error().Msgf("GET custom role failed: %v", retryErr)
-		return nil, statusCode, retryErr
+	var customRole = make(map[string]string)
+	for _, resource := range roles {
+		customRole[resource.Name] = resource.UID
 	}
+	return customRole, statusCode, nil
+}
 
-	if len(productCustomRoles.Resources) > 0 {
-		for _, resource := range productCustomRoles.Resources {
-			customRole[resource.Name] = resource.UID
-		}
+// GetRoles lists the custom roles of a product with their UID, description and bindable level
+func (c *MCSPIAMClient) GetRoles(ctx context.Context, serviceID string) ([]Role, int, error) {
+	it := c.Roles(serviceID)
+	roles, err := it.All(ctx)
+	if err != nil {
+		logger.Error().Msgf("GET custom role failed: %v", err)
+		return nil, it.StatusCode(), err
 	}
+	logger.Info().Msgf("GET succeeded with status %d, %d custom roles listed", it.StatusCode(), len(roles))
 
-	return customRole, statusCode, nil
+	return roles, it.StatusCode(), nil
 }
 
-func (c *MCSPIAMClient) GetProductDetails(serviceID string) (map[string]interface{}, int, error) {
+// GetProductDetails gets the product of serviceID. A product unknown to Account IAM is reported with a nil product
+// and the 404 status code.
+func (c *MCSPIAMClient) GetProductDetails(ctx context.Context, serviceID string) (*Product, int, error) {
 
 	productDetailsEndpoint := c.BaseURL + "/" + serviceID
-	responseProductActions, statusCode, err := c.Get(productDetailsEndpoint)
+	responseProductActions, statusCode, err := c.Get(ctx, productDetailsEndpoint)
 	if err != nil {
 		return nil, statusCode, fmt.Errorf("failed to do GET request: %v", err)
 	}
 	//log response body and status code
-	var productDetails map[string]interface{}
+	var productDetails *Product
 	if responseProductActions != nil && responseProductActions.Body != nil {
 		defer responseProductActions.Body.Close()
+		if statusCode == http.StatusNotFound {
+			return nil, statusCode, nil
+		}
 		if err := json.NewDecoder(responseProductActions.Body).Decode(&productDetails); err != nil && err != io.EOF {
 			log.Error(err, "failed to decode response results for product details")
 			return nil, statusCode, err
@@ -340,14 +376,20 @@ func (c *MCSPIAMClient) GetProductDetails(serviceID string) (map[string]interfac
 
 }
 
-// PostNewProduct function sends a POST request to the IAM API to register a new product.
-func (c *MCSPIAMClient) PostNewProduct(serviceID string) ([]byte, int, error) {
+// PostNewProduct function sends a POST request to the IAM API to register a new product with its display name and description.
+func (c *MCSPIAMClient) PostNewProduct(ctx context.Context, serviceID string, metadata ProductMetadata) ([]byte, int, error) {
 
 	var body []byte
 	var statusCode int
 
+	encodeBodyProduct, err := json.Marshal(metadata)
+	if err != nil {
+		log.Error(err, "failed to marshal request")
+		return nil, 0, err
+	}
+
 	customRolesEndpoint := c.BaseURL + "/" + serviceID
-	responseCustomRoles, statusCode, err := c.Post(customRolesEndpoint, bytes.NewReader(nil))
+	responseCustomRoles, statusCode, err := c.Post(ctx, customRolesEndpoint, bytes.NewReader(encodeBodyProduct))
 	if err != nil {
 		return nil, statusCode, fmt.Errorf("failed to do POST request: %w", err)
 	}
@@ -369,16 +411,25 @@ func (c *MCSPIAMClient) PostNewProduct(serviceID string) ([]byte, int, error) {
 
 }
 
-func (c *MCSPIAMClient) PostCustomRoles(v2CustomRole operatorv1alpha1.V2CustomRoles, serviceID string) ([]byte, int, error) {
+// PostCustomRoles function sends a POST request to the IAM API to create a custom role. It returns the created role,
+// or nil when the response does not describe it.
+func (c *MCSPIAMClient) PostCustomRoles(ctx context.Context, v2CustomRole operatorv1alpha1.V2CustomRoles, serviceID string) (*Role, int, error) {
 
-	var body []byte
+	var created *Role
 	var statusCode int
 	var finalErr error
 
+	bindableLevel := v2CustomRole.BindableLevel
+	if bindableLevel == "" {
+		bindableLevel = operatorv1alpha1.BindableLevelService
+	}
 	singleCustomRole := map[string]string{
 		"name":          v2CustomRole.Name,
 		"description":   v2CustomRole.Description,
-		"bindableLevel": "SERVICE",
+		"bindableLevel": bindableLevel,
+	}
+	if v2CustomRole.DisplayName != "" {
+		singleCustomRole["displayName"] = v2CustomRole.DisplayName
 	}
 
 	encodeBodyCustomRoles, err := json.Marshal(singleCustomRole)
@@ -389,8 +440,8 @@ func (c *MCSPIAMClient) PostCustomRoles(v2CustomRole operatorv1alpha1.V2CustomRo
 
 	customRolesEndpoint := c.BaseURL + "/" + serviceID + "/roles"
 	//retryhandler to avoid crashing the operator when IAM is down
-	retryErr := c.retry.RetryHandler(func() error {
-		responseCustomRoles, code, err := c.Post(customRolesEndpoint, bytes.NewReader(encodeBodyCustomRoles))
+	retryErr := c.retry.RetryHandler(ctx, func() error {
+		responseCustomRoles, code, err := c.Post(ctx, customRolesEndpoint, bytes.NewReader(encodeBodyCustomRoles))
 		statusCode = code
 		if statusCode >= 400 && statusCode < 500 {
 			finalErr = fmt.Errorf("POST returned client error: %d", statusCode)
@@ -411,11 +462,10 @@ func (c *MCSPIAMClient) PostCustomRoles(v2CustomRole operatorv1alpha1.V2CustomRo
 		//nil check for responseCustomRoles to avoid nil pointer dereference which causes a panic
 		if responseCustomRoles != nil && responseCustomRoles.Body != nil {
 			defer responseCustomRoles.Body.Close()
-			if data, err := io.ReadAll(responseCustomRoles.Body); err == nil {
-				body = data
-			} else {
-				finalErr = err
-				return err
+			// the role is created even when the response cannot be decoded, the POST must not be retried
+			if err := json.NewDecoder(responseCustomRoles.Body).Decode(&created); err != nil && err != io.EOF {
+				log.Error(err, "failed to decode the created custom role")
+				created = nil
 			}
 		}
 		if responseCustomRoles == nil {
@@ -431,34 +481,61 @@ func (c *MCSPIAMClient) PostCustomRoles(v2CustomRole operatorv1alpha1.V2CustomRo
 		return nil, statusCode, retryErr
 	}
 
-	return body, statusCode, nil
+	return created, statusCode, nil
 
 }
 
-func (c *MCSPIAMClient) UpdateCustomRoles(v2CustomRole operatorv1alpha1.V2CustomRoles, serviceID string, UID string) ([]byte, int, error) {
+// UpdateCustomRoles function sends a PATCH request to the IAM API to update the description, display name
+// and bindable level which are set of the custom role identified by UID.
+func (c *MCSPIAMClient) UpdateCustomRoles(ctx context.Context, v2CustomRole operatorv1alpha1.V2CustomRoles, serviceID string, UID string) ([]byte, int, error) {
+
+	singleUpdateCustomRole := map[string]string{}
+	if v2CustomRole.Description != "" {
+		singleUpdateCustomRole["description"] = v2CustomRole.Description
+	}
+	if v2CustomRole.DisplayName != "" {
+		singleUpdateCustomRole["displayName"] = v2CustomRole.DisplayName
+	}
+	if v2CustomRole.BindableLevel != "" {
+		singleUpdateCustomRole["bindableLevel"] = v2CustomRole.BindableLevel
+	}
+
+	customRolesUpdateEndpoint := c.BaseURL + "/" + serviceID + "/roles" + "/" + UID
+	return c.patch(ctx, customRolesUpdateEndpoint, singleUpdateCustomRole, "custom role")
+}
+
+// UpdateProduct function sends a PATCH request to the IAM API to update the display name and description of a product.
+func (c *MCSPIAMClient) UpdateProduct(ctx context.Context, serviceID string, metadata ProductMetadata) ([]byte, int, error) {
+	return c.patch(ctx, c.BaseURL+"/"+serviceID, metadata, "product")
+}
+
+// UpdateActionProductLevel function sends a PATCH request to the IAM API to update the description of a product level action.
+func (c *MCSPIAMClient) UpdateActionProductLevel(ctx context.Context, serviceID string, actionName string, description string) ([]byte, int, error) {
+	productActionEndpoint := c.BaseURL + "/" + serviceID + "/actions/" + actionName
+	return c.patch(ctx, productActionEndpoint, map[string]string{"description": description}, "product level action")
+}
+
+// patch sends payload as the JSON body of a PATCH request to endpoint, retrying on server errors.
+// resource names what is patched in the logs.
+func (c *MCSPIAMClient) patch(ctx context.Context, endpoint string, payload any, resource string) ([]byte, int, error) {
 
 	var body []byte
 	var statusCode int
 	var finalErr error
 
-	singleUpdateCustomRole := map[string]string{
-		"description": v2CustomRole.Description,
-	}
-
-	encodeBodyUpdateCustomRoles, err := json.Marshal(singleUpdateCustomRole)
+	encodeBody, err := json.Marshal(payload)
 	if err != nil {
-		log.Error(err, "failed to marshal response")
+		log.Error(err, "failed to marshal request")
 		return nil, 0, err
 	}
 
-	customRolesUpdateEndpoint := c.BaseURL + "/" + serviceID + "/roles" + "/" + UID
 	//retryhandler to avoid crashing the operator when IAM is down
-	retryErr := c.retry.RetryHandler(func() error {
-		responseUpdateCustomRoles, sc, err := c.Patch(customRolesUpdateEndpoint, bytes.NewReader(encodeBodyUpdateCustomRoles))
+	retryErr := c.retry.RetryHandler(ctx, func() error {
+		response, sc, err := c.Patch(ctx, endpoint, bytes.NewReader(encodeBody))
 		statusCode = sc
 
 		if err != nil {
-			logger.Info().Msgf("PATCH failed for %s, will retry if allowed", customRolesUpdateEndpoint)
+			logger.Info().Msgf("PATCH failed for %s, will retry if allowed", endpoint)
 			finalErr = fmt.Errorf("failed to do PATCH request: %v", err)
 			return finalErr
 		}
@@ -472,11 +549,11 @@ func (c *MCSPIAMClient) UpdateCustomRoles(v2CustomRole operatorv1alpha1.V2Custom
 			finalErr = fmt.Errorf("failed to do PATCH request: %d", statusCode)
 			return finalErr
 		}
-		//nil check for responseUpdateCustomRoles to avoid nil pointer dereference which causes a panic
-		if responseUpdateCustomRoles != nil && responseUpdateCustomRoles.Body != nil {
-			defer responseUpdateCustomRoles.Body.Close()
+		//nil check for response to avoid nil pointer dereference which causes a panic
+		if response != nil && response.Body != nil {
+			defer response.Body.Close()
 
-			if data, err := io.ReadAll(responseUpdateCustomRoles.Body); err == nil {
+			if data, err := io.ReadAll(response.Body); err == nil {
 				body = data
 			} else {
 				logger.Info().Msg("Failed to read response body")
@@ -484,7 +561,7 @@ func (c *MCSPIAMClient) UpdateCustomRoles(v2CustomRole operatorv1alpha1.V2Custom
 				return err
 			}
 		}
-		if responseUpdateCustomRoles == nil {
+		if response == nil {
 			logger.Warn().Msg("Response or Body is nil, skipping body read and close")
 		}
 
@@ -493,7 +570,7 @@ func (c *MCSPIAMClient) UpdateCustomRoles(v2CustomRole operatorv1alpha1.V2Custom
 	})
 
 	if retryErr != nil {
-		logger.Error().Msgf("PATCH custom role failed: %v", retryErr)
+		logger.Error().Msgf("PATCH %s failed: %v", resource, retryErr)
 		return nil, statusCode, retryErr
 	}
 
@@ -501,7 +578,7 @@ func (c *MCSPIAMClient) UpdateCustomRoles(v2CustomRole operatorv1alpha1.V2Custom
 
 }
 
-func (c *MCSPIAMClient) DeleteCustomRoles(instance *operatorv1alpha1.RoleActionConfig, UID string) ([]byte, int, error) {
+func (c *MCSPIAMClient) DeleteCustomRoles(ctx context.Context, instance *operatorv1alpha1.RoleActionConfig, UID string) ([]byte, int, error) {
 
 	serviceID := instance.Spec.ServiceID
 
@@ -511,8 +588,8 @@ func (c *MCSPIAMClient) DeleteCustomRoles(instance *operatorv1alpha1.RoleActionC
 
 	customRolesDeleteEndpoint := c.BaseURL + "/" + serviceID + "/roles" + "/" + UID
 	//retryhandler to avoid crashing the operator when IAM is down
-	retryErr := c.retry.RetryHandler(func() error {
-		responseDeleteCustomRoles, sc, err := c.Delete(customRolesDeleteEndpoint)
+	retryErr := c.retry.RetryHandler(ctx, func() error {
+		responseDeleteCustomRoles, sc, err := c.Delete(ctx, customRolesDeleteEndpoint)
 		statusCode = sc
 
 		if err != nil {
@@ -560,7 +637,7 @@ func (c *MCSPIAMClient) DeleteCustomRoles(instance *operatorv1alpha1.RoleActionC
 }
 
 // PostActionsProductLevel function sends a POST request to the IAM API to add a new action to a specific product. The action is specified by the action string and associated with the serviceID.
-func (c *MCSPIAMClient) PostActionsProductLevel(action string, serviceID string) ([]byte, int, error) {
+func (c *MCSPIAMClient) PostActionsProductLevel(ctx context.Context, action string, description string, serviceID string) ([]byte, int, error) {
 
 	productActionsEndpoint := c.BaseURL + "/" + serviceID + "/actions"
 
@@ -568,6 +645,9 @@ func (c *MCSPIAMClient) PostActionsProductLevel(action string, serviceID string)
 	singleAction := map[string]string{
 		"name": action,
 	}
+	if description != "" {
+		singleAction["description"] = description
+	}
 
 	encodeBodyProductActions, err := json.Marshal(singleAction)
 	if err != nil {
@@ -579,8 +659,8 @@ func (c *MCSPIAMClient) PostActionsProductLevel(action string, serviceID string)
 	var statusCode int
 	var finalErr error
 	//retryhandler to avoid crashing the operator when IAM is down
-	retryErr := c.retry.RetryHandler(func() error {
-		responseProductActions, sc, err := c.Post(productActionsEndpoint, bytes.NewReader(encodeBodyProductActions))
+	retryErr := c.retry.RetryHandler(ctx, func() error {
+		responseProductActions, sc, err := c.Post(ctx, productActionsEndpoint, bytes.NewReader(encodeBodyProductActions))
 		statusCode = sc
 
 		if err != nil {
@@ -627,82 +707,20 @@ func (c *MCSPIAMClient) PostActionsProductLevel(action string, serviceID string)
 
 }
 
-func (c *MCSPIAMClient) GetActionsProductLevel(serviceID string) ([]map[string]string, int, error) {
-	var statusCode int
-	var finalErr error
-	var actionArray []map[string]string
-	var Actions ActionDefinition
-
-	productActionsEndpoint := c.BaseURL + "/" + serviceID + "/actions"
-
-	retryErr := c.retry.RetryHandler(func() error {
-		// Parse the endpoint into a url.URL struct
-		reqURL, err := url.Parse(productActionsEndpoint)
-		if err != nil {
-			log.Error(err, "Failed to parse productActionsEndpoint for GetActionsProductLevel request", productActionsEndpoint)
-			finalErr = fmt.Errorf("failed to parse productActionsEndpoint for GetActionsProductLevel request %v", err)
-			return retry.NewPermanentError(finalErr)
-		}
-
-		// Add pageSize query parameter to the request
-		query := reqURL.Query()
-		query.Set(pageSize, maxPageSize)
-		reqURL.RawQuery = query.Encode()
-
-		// Make GET request with updated URL
-		responseProductActions, sc, err := c.Get(reqURL.String())
-		statusCode = sc
-
-		if err != nil {
-			logger.Info().Msgf("GET failed for %s, will retry if allowed", reqURL.String())
-			finalErr = fmt.Errorf("failed to do GET request: %v", err)
-			return finalErr
-		}
-
-		if statusCode >= 400 && statusCode < 500 {
-			finalErr = fmt.Errorf("GET returned client error: %d", statusCode)
-			return retry.NewPermanentError(finalErr)
-		}
-
-		if statusCode >= 500 {
-			finalErr = fmt.Errorf("GET returned server error: %d", statusCode)
-			return finalErr
-		}
-
-		if responseProductActions != nil && responseProductActions.Body != nil {
-			defer responseProductActions.Body.Close()
-			if err := json.NewDecoder(responseProductActions.Body).Decode(&Actions); err != nil && err != io.EOF {
-				log.Error(err, "failed to decode response results for actions at product level")
-				finalErr = err
-				return err
-			}
-		}
-		if responseProductActions == nil {
-			logger.Warn().Msg("Response or Body is nil, skipping decode")
-		}
-
-		logger.Info().Msgf("GET succeeded with status %d", statusCode)
-		return nil
-	})
-
-	if retryErr != nil {
-		logger.Error().Msgf("GET product level actions failed: %v", finalErr)
-		return nil, statusCode, retryErr
-	}
-
-	if len(Actions.Resources) > 0 {
-		for _, resource := range Actions.Resources {
-			actionArray = append(actionArray, map[string]string{
-				"name": resource.Name,
-			})
-		}
+func (c *MCSPIAMClient) GetActionsProductLevel(ctx context.Context, serviceID string) ([]Action, int, error) {
+	it := c.ProductActions(serviceID)
+	actions, err := it.All(ctx)
+	if err != nil {
+		logger.Error().Msgf("GET product level actions failed: %v", err)
+		return nil, it.StatusCode(), err
 	}
+	logger.Info().Msgf("GET succeeded with status %d, %d product level actions listed", it.StatusCode(), len(actions))
 
-	return actionArray, statusCode, nil
+	return actions, it.StatusCode(), nil
 }
 
 // DeleteActionsProductLevel function sends a DELETE request to the IAM API to remove a specific action associated with a given serviceID. The action is identified by the serviceID and actionName.
-func (c *MCSPIAMClient) DeleteActionsProductLevel(serviceID string, actionName string) ([]byte, int, error) {
+func (c *MCSPIAMClient) DeleteActionsProductLevel(ctx context.Context, serviceID string, actionName string) ([]byte, int, error) {
 
 	productActionsEndpoint := c.BaseURL + "/" + serviceID + "/actions" + "/" + actionName
 
@@ -710,8 +728,8 @@ func (c *MCSPIAMClient) DeleteActionsProductLevel(serviceID string, actionName s
 	var statusCode int
 	var finalErr error
 	//retryhandler to avoid crashing the operator when IAM is down
-	retryErr := c.retry.RetryHandler(func() error {
-		responseProductActions, sc, err := c.Delete(productActionsEndpoint)
+	retryErr := c.retry.RetryHandler(ctx, func() error {
+		responseProductActions, sc, err := c.Delete(ctx, productActionsEndpoint)
 		statusCode = sc
 
 		if err != nil {
@@ -756,82 +774,22 @@ func (c *MCSPIAMClient) DeleteActionsProductLevel(serviceID string, actionName s
 	return body, statusCode, nil
 }
 
-func (c *MCSPIAMClient) GetActionsRoleLevel(serviceID string, roleUID string) ([]map[string]string, int, error) {
-	var statusCode int
-	var finalErr error
-	var Actions ActionDefinition
-	var actionArray = []map[string]string{}
-
-	productActionsEndpoint := c.BaseURL + "/" + serviceID + "/roles/" + roleUID + "/actions"
-
-	retryErr := c.retry.RetryHandler(func() error {
-		// Parse the endpoint
-		reqURL, err := url.Parse(productActionsEndpoint)
-		if err != nil {
-			log.Error(err, "Failed to parse productActionsEndpoint for GetActionsRoleLevel request", productActionsEndpoint)
-			finalErr = fmt.Errorf("failed to parse productActionsEndpoint for GetActionsRoleLevel request %v", err)
-			return retry.NewPermanentError(finalErr)
-		}
-
-		// Add pageSize as query param
-		query := reqURL.Query()
-		query.Set(pageSize, maxPageSize)
-		reqURL.RawQuery = query.Encode()
-
-		// Perform GET
-		responseProductActions, sc, err := c.Get(reqURL.String())
-		statusCode = sc
-
-		if err != nil {
-			logger.Info().Msgf("GET failed for %s, will retry if allowed", reqURL.String())
-			finalErr = fmt.Errorf("failed to do GET request: %v", err)
-			return finalErr
-		}
-
-		if statusCode >= 400 && statusCode < 500 {
-			finalErr = fmt.Errorf("GET returned client error: %d", statusCode)
-			return retry.NewPermanentError(finalErr)
-		}
-
-		if statusCode >= 500 {
-			finalErr = fmt.Errorf("GET returned server error: %d", statusCode)
-			return finalErr
-		}
-
-		if responseProductActions != nil && responseProductActions.Body != nil {
-			defer responseProductActions.Body.Close()
-			if err := json.NewDecoder(responseProductActions.Body).Decode(&Actions); err != nil && err != io.EOF {
-				log.Error(err, "failed to decode response results for actions at role level")
-				finalErr = err
-				return err
-			}
-		}
-		if responseProductActions == nil {
-			logger.Warn().Msg("Response or Body is nil, skipping body decode")
-		}
-
-		logger.Info().Msgf("GET succeeded with status %d", statusCode)
-		return nil
-	})
-
-	if retryErr != nil {
-		logger.Error().Msgf("GET custom role actions failed: %v", retryErr)
-		return nil, statusCode, retryErr
+func (c *MCSPIAMClient) GetActionsRoleLevel(ctx context.Context, serviceID string, roleUID string) ([]Action, int, error) {
+	it := c.RoleActions(serviceID, roleUID)
+	actions, err := it.All(ctx)
+	if err != nil {
+		logger.Error().Msgf("GET custom role actions failed: %v", err)
+		return nil, it.StatusCode(), err
 	}
+	logger.Info().Msgf("GET succeeded with status %d, %d custom role actions listed", it.StatusCode(), len(actions))
 
-	// Format response
-	if len(Actions.Resources) > 0 {
-		for _, resource := range Actions.Resources {
-			actionArray = append(actionArray, map[string]string{
-				"name": resource.Name,
-			})
-		}
+	if actions == nil {
+		actions = []Action{}
 	}
-
-	return actionArray, statusCode, nil
+	return actions, it.StatusCode(), nil
 }
 
-func (c *MCSPIAMClient) PostActionsRoleLevel(action string, roleUID string, serviceID string) ([]byte, int, error) {
+func (c *MCSPIAMClient) PostActionsRoleLevel(ctx context.Context, action string, roleUID string, serviceID string) ([]byte, int, error) {
 	var finalErr error
 
 	productActionsEndpoint := c.BaseURL + "/" + serviceID + "/roles/" + roleUID + "/actions"
@@ -846,8 +804,8 @@ func (c *MCSPIAMClient) PostActionsRoleLevel(action string, roleUID string, serv
 	var statusCode int
 
 	//retryhandler to avoid crashing the operator when IAM is down
-	err = c.retry.RetryHandler(func() error {
-		responseProductActions, sc, err := c.Post(productActionsEndpoint, bytes.NewReader(encodeBodyProductActions))
+	err = c.retry.RetryHandler(ctx, func() error {
+		responseProductActions, sc, err := c.Post(ctx, productActionsEndpoint, bytes.NewReader(encodeBodyProductActions))
 		statusCode = sc
 
 		if err != nil {
@@ -890,15 +848,15 @@ func (c *MCSPIAMClient) PostActionsRoleLevel(action string, roleUID string, serv
 	return body, statusCode, nil
 }
 
-func (c *MCSPIAMClient) DeleteActionsRoleLevel(serviceID string, roleUID string, actionName string) ([]byte, int, error) {
+func (c *MCSPIAMClient) DeleteActionsRoleLevel(ctx context.Context, serviceID string, roleUID string, actionName string) ([]byte, int, error) {
 	var body []byte
 	var statusCode int
 	var finalErr error
 
 	customRolesActionDeleteEndpoint := c.BaseURL + "/" + serviceID + "/roles/" + roleUID + "/actions/" + actionName
 	//retryhandler to avoid crashing the operator when IAM is down
-	err := c.retry.RetryHandler(func() error {
-		responseDeleteCustomRolesAction, sc, err := c.Delete(customRolesActionDeleteEndpoint)
+	err := c.retry.RetryHandler(ctx, func() error {
+		responseDeleteCustomRolesAction, sc, err := c.Delete(ctx, customRolesActionDeleteEndpoint)
 		statusCode = sc
 
 		if err != nil {
@@ -0,0 +1,126 @@
+package account_iam
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/IBM/ibm-user-management-operator/internal/retry"
+)
+
+// ServiceURL returns the URL of the Account IAM service deployed in a namespace
+func ServiceURL(namespace string) string {
+	return fmt.Sprintf("https://account-iam.%s.svc.cluster.local:9445", namespace)
+}
+
+// ClientConfig holds the settings of the client of an AccountIAM
+type ClientConfig struct {
+	// Namespace is the namespace the Account IAM service is deployed in
+	Namespace string
+	// APIKey is the key exchanged for the tokens of the Account IAM API
+	APIKey string
+	// CABundle holds the PEM encoded CA certificates the Account IAM service certificate is verified against
+	CABundle []byte
+	// APIKeySource re-reads the API key when Account IAM rejects a token. It is not compared,
+	// the client keeps the source it was built with.
+	APIKeySource func(ctx context.Context) (string, error)
+}
+
+// ClientFactory provides the IAMClient of each AccountIAM instance
+type ClientFactory interface {
+	// ClientFor returns the client of the AccountIAM identified by key, built from config
+	ClientFor(key string, config ClientConfig) (IAMClient, error)
+	// TokenURL returns the token endpoint of the Account IAM service deployed in namespace
+	TokenURL(namespace string) string
+	// Evict drops the cached client of the AccountIAM identified by key, once the AccountIAM is deleted
+	Evict(key string)
+}
+
+// cachedClient is a client with the settings it was built from
+type cachedClient struct {
+	config ClientConfig
+	client IAMClient
+}
+
+// FactoryOptions configures the clients built by a ClientFactory
+type FactoryOptions struct {
+	// InsecureSkipVerify disables the verification of the Account IAM service certificate, for debugging only
+	InsecureSkipVerify bool
+	// RequestTimeout bounds each request sent to Account IAM, zero means no timeout
+	RequestTimeout time.Duration
+	// ServiceURL overrides the URL of the Account IAM service deployed in a namespace,
+	// to point the clients to a stand-in server in tests and local development
+	ServiceURL func(namespace string) string
+}
+
+// clientFactory caches one MCSPIAMClient per AccountIAM. A client is rebuilt when the namespace,
+// the API key or the CA bundle of its AccountIAM changes, so it never carries stale settings.
+type clientFactory struct {
+	retry   *retry.Retry
+	options FactoryOptions
+
+	mu      sync.Mutex
+	clients map[string]cachedClient
+}
+
+// NewClientFactory returns a ClientFactory building MCSPIAMClients configured with options,
+// which retry with retryHandler. It is safe for concurrent use.
+func NewClientFactory(retryHandler *retry.Retry, options FactoryOptions) ClientFactory {
+	return &clientFactory{
+		retry:   retryHandler,
+		options: options,
+		clients: map[string]cachedClient{},
+	}
+}
+
+func (f *clientFactory) ClientFor(key string, config ClientConfig) (IAMClient, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if cached, ok := f.clients[key]; ok && cached.config.equal(config) {
+		return cached.client, nil
+	}
+
+	tlsConfig, err := NewTLSConfig(config.Namespace, config.CABundle, f.options.InsecureSkipVerify)
+	if err != nil {
+		return nil, err
+	}
+	client, err := NewMCSPIAMClient(f.serviceURL(config.Namespace)+ProductsPath, config.APIKey, tlsConfig, f.options.RequestTimeout, f.retry)
+	if err != nil {
+		return nil, err
+	}
+	if mcspClient, ok := client.(*MCSPIAMClient); ok {
+		mcspClient.APIKeySource = config.APIKeySource
+	}
+	f.clients[key] = cachedClient{
+		config: config,
+		client: client,
+	}
+	return client, nil
+}
+
+func (f *clientFactory) Evict(key string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.clients, key)
+}
+
+func (f *clientFactory) TokenURL(namespace string) string {
+	return f.serviceURL(namespace) + "/" + tokenUrl
+}
+
+// serviceURL returns the URL the clients reach the Account IAM service of a namespace at
+func (f *clientFactory) serviceURL(namespace string) string {
+	if f.options.ServiceURL != nil {
+		return f.options.ServiceURL(namespace)
+	}
+	return ServiceURL(namespace)
+}
+
+// equal reports whether both configurations build the same client
+func (c ClientConfig) equal(other ClientConfig) bool {
+	return c.Namespace == other.Namespace && c.APIKey == other.APIKey && bytes.Equal(c.CABundle, other.CABundle)
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package account_iam
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/IBM/ibm-user-management-operator/internal/retry"
+)
+
+var _ = Describe("Client factory", func() {
+	config := ClientConfig{Namespace: "default", APIKey: "api-key"}
+
+	It("should cache the client of an AccountIAM until it is evicted", func() {
+		factory := NewClientFactory(&retry.Retry{}, FactoryOptions{InsecureSkipVerify: true})
+		client, err := factory.ClientFor("accountiam-uid", config)
+		Expect(err).NotTo(HaveOccurred())
+
+		cached, err := factory.ClientFor("accountiam-uid", config)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cached).To(BeIdenticalTo(client))
+
+		By("rebuilding the client once evicted")
+		factory.Evict("accountiam-uid")
+		rebuilt, err := factory.ClientFor("accountiam-uid", config)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rebuilt).NotTo(BeIdenticalTo(client))
+
+		By("ignoring an unknown AccountIAM")
+		factory.Evict("unknown-uid")
+	})
+})
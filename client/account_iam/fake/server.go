@@ -0,0 +1,699 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fake provides an in-process stand-in of the Account IAM products and API key token
+// endpoints, keeping its state in memory, to run the client and the RoleActionConfig sync offline.
+package fake
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	productsPath = "/api/2.0/products"
+	tokenPath    = "/api/2.0/accounts/global_account/apikeys/token"
+)
+
+// Role is a custom role of a product
+type Role struct {
+	UID           string
+	Name          string
+	Description   string
+	DisplayName   string
+	BindableLevel string
+	Actions       []string
+}
+
+// Product is the state of a product registered in the server
+type Product struct {
+	DisplayName string
+	Description string
+	Actions     []string
+	// ActionDescriptions are the descriptions of the actions which have one
+	ActionDescriptions map[string]string
+	Roles              []Role
+}
+
+// timestamps are the times a product or role was created and last updated
+type timestamps struct {
+	createdAt time.Time
+	updatedAt time.Time
+}
+
+func newTimestamps() timestamps {
+	now := time.Now().UTC()
+	return timestamps{createdAt: now, updatedAt: now}
+}
+
+// touch records an update
+func (t *timestamps) touch() {
+	t.updatedAt = time.Now().UTC()
+}
+
+// addTo adds the timestamps to the representation of a resource in the responses of the API
+func (t timestamps) addTo(resource map[string]any) map[string]any {
+	resource["createdAt"] = t.createdAt.Format(time.RFC3339)
+	resource["updatedAt"] = t.updatedAt.Format(time.RFC3339)
+	return resource
+}
+
+type product struct {
+	timestamps
+	displayName        string
+	description        string
+	actions            map[string]bool
+	actionDescriptions map[string]string
+	roles              map[string]*role
+}
+
+type role struct {
+	timestamps
+	uid           string
+	name          string
+	description   string
+	displayName   string
+	bindableLevel string
+	actions       map[string]bool
+}
+
+func newProduct() *product {
+	return &product{
+		timestamps:         newTimestamps(),
+		actions:            map[string]bool{},
+		actionDescriptions: map[string]string{},
+		roles:              map[string]*role{},
+	}
+}
+
+// resource returns the representation of the product in the responses of the API
+func (p *product) resource(serviceID string) map[string]any {
+	return p.addTo(map[string]any{"id": serviceID, "displayName": p.displayName, "description": p.description})
+}
+
+// resource returns the representation of the role in the responses of the API
+func (r *role) resource() map[string]any {
+	return r.addTo(map[string]any{
+		"uid":           r.uid,
+		"name":          r.name,
+		"description":   r.description,
+		"displayName":   r.displayName,
+		"bindableLevel": r.bindableLevel,
+	})
+}
+
+type failure struct {
+	statusCode int
+	remaining  int
+}
+
+// Server is a fake Account IAM serving over httptest. Its knobs can be changed while it serves.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	apiKey   string
+	latency  time.Duration
+	pageSize int
+	tokenTTL time.Duration
+	failures []failure
+	products map[string]*product
+	tokens   map[string]time.Time
+	nextID   int
+	requests map[string]int
+}
+
+// Option configures a Server
+type Option func(*Server)
+
+// WithAPIKey makes the server only issue tokens for apiKey, any key is accepted otherwise
+func WithAPIKey(apiKey string) Option {
+	return func(s *Server) { s.apiKey = apiKey }
+}
+
+// WithLatency delays every response of the server
+func WithLatency(latency time.Duration) Option {
+	return func(s *Server) { s.latency = latency }
+}
+
+// WithPageSize caps the pages of the listings, below the page size the client requests
+func WithPageSize(pageSize int) Option {
+	return func(s *Server) { s.pageSize = pageSize }
+}
+
+// WithTokenTTL sets the lifetime of the issued tokens, one hour by default
+func WithTokenTTL(ttl time.Duration) Option {
+	return func(s *Server) { s.tokenTTL = ttl }
+}
+
+// NewServer starts a plain HTTP fake Account IAM. The server must be closed by the caller.
+func NewServer(opts ...Option) *Server {
+	s := newServer(opts...)
+	s.Server = httptest.NewServer(s.handler())
+	return s
+}
+
+// NewTLSServer starts a fake Account IAM serving HTTPS with the httptest certificate.
+// The server must be closed by the caller.
+func NewTLSServer(opts ...Option) *Server {
+	s := newServer(opts...)
+	s.Server = httptest.NewTLSServer(s.handler())
+	return s
+}
+
+func newServer(opts ...Option) *Server {
+	s := &Server{
+		tokenTTL: time.Hour,
+		products: map[string]*product{},
+		tokens:   map[string]time.Time{},
+		requests: map[string]int{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// SetLatency changes the delay of every response
+func (s *Server) SetLatency(latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = latency
+}
+
+// SetPageSize changes the size cap of the pages of the listings, zero removes it
+func (s *Server) SetPageSize(pageSize int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pageSize = pageSize
+}
+
+// SetAPIKey changes the API key the server issues tokens for, like a rotation of the key
+func (s *Server) SetAPIKey(apiKey string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.apiKey = apiKey
+}
+
+// InjectFailures makes the next count requests, token requests included, fail with statusCode,
+// e.g. http.StatusServiceUnavailable or http.StatusTooManyRequests
+func (s *Server) InjectFailures(statusCode int, count int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, failure{statusCode: statusCode, remaining: count})
+}
+
+// ExpireTokens revokes all the issued tokens, so the next requests are rejected with 401
+func (s *Server) ExpireTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens = map[string]time.Time{}
+}
+
+// Requests returns how many requests were received for the method and path, e.g. "POST /api/2.0/products/p/actions"
+func (s *Server) Requests(method string, path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[method+" "+path]
+}
+
+// RegisterProduct seeds the server with a product and its state
+func (s *Server) RegisterProduct(serviceID string, state Product) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := newProduct()
+	p.displayName, p.description = state.DisplayName, state.Description
+	for _, action := range state.Actions {
+		p.actions[action] = true
+	}
+	for action, description := range state.ActionDescriptions {
+		p.actionDescriptions[action] = description
+	}
+	for _, r := range state.Roles {
+		seeded := &role{
+			timestamps:    newTimestamps(),
+			uid:           r.UID,
+			name:          r.Name,
+			description:   r.Description,
+			displayName:   r.DisplayName,
+			bindableLevel: r.BindableLevel,
+			actions:       map[string]bool{},
+		}
+		if seeded.uid == "" {
+			seeded.uid = s.newUID()
+		}
+		for _, action := range r.Actions {
+			seeded.actions[action] = true
+		}
+		p.roles[seeded.uid] = seeded
+	}
+	s.products[serviceID] = p
+}
+
+// Product returns the state of a registered product, with its actions and roles sorted by name
+func (s *Server) Product(serviceID string) (Product, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.products[serviceID]
+	if !ok {
+		return Product{}, false
+	}
+	state := Product{
+		DisplayName:        p.displayName,
+		Description:        p.description,
+		Actions:            sortedKeys(p.actions),
+		ActionDescriptions: map[string]string{},
+	}
+	for action, description := range p.actionDescriptions {
+		state.ActionDescriptions[action] = description
+	}
+	for _, r := range p.roles {
+		state.Roles = append(state.Roles, Role{
+			UID:           r.uid,
+			Name:          r.name,
+			Description:   r.description,
+			DisplayName:   r.displayName,
+			BindableLevel: r.bindableLevel,
+			Actions:       sortedKeys(r.actions),
+		})
+	}
+	sort.Slice(state.Roles, func(i, j int) bool { return state.Roles[i].Name < state.Roles[j].Name })
+	return state, true
+}
+
+func (s *Server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+tokenPath, s.issueToken)
+	mux.HandleFunc("GET "+productsPath+"/{product}", s.authorized(s.getProduct))
+	mux.HandleFunc("POST "+productsPath+"/{product}", s.authorized(s.postProduct))
+	mux.HandleFunc("PATCH "+productsPath+"/{product}", s.authorized(s.patchProduct))
+	mux.HandleFunc("GET "+productsPath+"/{product}/actions", s.authorized(s.listProductActions))
+	mux.HandleFunc("POST "+productsPath+"/{product}/actions", s.authorized(s.postProductAction))
+	mux.HandleFunc("PATCH "+productsPath+"/{product}/actions/{action}", s.authorized(s.patchProductAction))
+	mux.HandleFunc("DELETE "+productsPath+"/{product}/actions/{action}", s.authorized(s.deleteProductAction))
+	mux.HandleFunc("GET "+productsPath+"/{product}/roles", s.authorized(s.listRoles))
+	mux.HandleFunc("POST "+productsPath+"/{product}/roles", s.authorized(s.postRole))
+	mux.HandleFunc("PATCH "+productsPath+"/{product}/roles/{role}", s.authorized(s.patchRole))
+	mux.HandleFunc("DELETE "+productsPath+"/{product}/roles/{role}", s.authorized(s.deleteRole))
+	mux.HandleFunc("GET "+productsPath+"/{product}/roles/{role}/actions", s.authorized(s.listRoleActions))
+	mux.HandleFunc("POST "+productsPath+"/{product}/roles/{role}/actions", s.authorized(s.postRoleAction))
+	mux.HandleFunc("DELETE "+productsPath+"/{product}/roles/{role}/actions/{action}", s.authorized(s.deleteRoleAction))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if latency := s.count(r); latency > 0 {
+			select {
+			case <-time.After(latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if statusCode := s.nextFailure(); statusCode != 0 {
+			if statusCode == http.StatusTooManyRequests {
+				w.Header().Set("Retry-After", "1")
+			}
+			writeError(w, statusCode, "injected failure")
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// count records a request and returns the latency to apply to it
+func (s *Server) count(r *http.Request) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests[r.Method+" "+r.URL.Path]++
+	return s.latency
+}
+
+// nextFailure consumes an injected failure and returns its status code, zero if there is none
+func (s *Server) nextFailure() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for len(s.failures) > 0 {
+		f := &s.failures[0]
+		if f.remaining > 0 {
+			f.remaining--
+			return f.statusCode
+		}
+		s.failures = s.failures[1:]
+	}
+	return 0
+}
+
+func (s *Server) issueToken(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		APIKey string `json:"apikey"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if body.APIKey == "" || (s.apiKey != "" && body.APIKey != s.apiKey) {
+		writeError(w, http.StatusUnauthorized, "invalid API key")
+		return
+	}
+
+	s.nextID++
+	expiry := time.Now().Add(s.tokenTTL)
+	token := newToken(s.nextID, expiry)
+	s.tokens[token] = expiry
+	writeJSON(w, http.StatusOK, map[string]string{"token": token})
+}
+
+// authorized rejects the requests without a valid token and the requests for unknown products
+func (s *Server) authorized(next func(http.ResponseWriter, *http.Request, *product)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if expiry, ok := s.tokens[token]; !ok || time.Now().After(expiry) {
+			writeError(w, http.StatusUnauthorized, "invalid or expired token")
+			return
+		}
+
+		p := s.products[r.PathValue("product")]
+		if p == nil && !(r.Method == http.MethodPost && r.URL.Path == productsPath+"/"+r.PathValue("product")) {
+			writeError(w, http.StatusNotFound, "product not found")
+			return
+		}
+		next(w, r, p)
+	}
+}
+
+func (s *Server) getProduct(w http.ResponseWriter, r *http.Request, p *product) {
+	writeJSON(w, http.StatusOK, p.resource(r.PathValue("product")))
+}
+
+func (s *Server) postProduct(w http.ResponseWriter, r *http.Request, p *product) {
+	if p != nil {
+		writeError(w, http.StatusConflict, "product already registered")
+		return
+	}
+	// the display name and description are optional, the body may be empty
+	var body struct {
+		DisplayName string `json:"displayName"`
+		Description string `json:"description"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && err != io.EOF {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	p = newProduct()
+	p.displayName, p.description = body.DisplayName, body.Description
+	s.products[r.PathValue("product")] = p
+	writeJSON(w, http.StatusCreated, p.resource(r.PathValue("product")))
+}
+
+func (s *Server) patchProduct(w http.ResponseWriter, r *http.Request, p *product) {
+	var body struct {
+		DisplayName *string `json:"displayName"`
+		Description *string `json:"description"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if body.DisplayName != nil {
+		p.displayName = *body.DisplayName
+	}
+	if body.Description != nil {
+		p.description = *body.Description
+	}
+	p.touch()
+	writeJSON(w, http.StatusOK, p.resource(r.PathValue("product")))
+}
+
+func (s *Server) listProductActions(w http.ResponseWriter, r *http.Request, p *product) {
+	s.writePage(w, r, actionResources(p.actions, p.actionDescriptions))
+}
+
+func (s *Server) postProductAction(w http.ResponseWriter, r *http.Request, p *product) {
+	var body struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Name == "" {
+		writeError(w, http.StatusBadRequest, "missing action name")
+		return
+	}
+	if p.actions[body.Name] {
+		writeError(w, http.StatusConflict, "action already exists")
+		return
+	}
+	p.actions[body.Name] = true
+	if body.Description != "" {
+		p.actionDescriptions[body.Name] = body.Description
+	}
+	writeJSON(w, http.StatusCreated, map[string]string{"name": body.Name, "description": body.Description})
+}
+
+func (s *Server) patchProductAction(w http.ResponseWriter, r *http.Request, p *product) {
+	action := r.PathValue("action")
+	if !p.actions[action] {
+		writeError(w, http.StatusNotFound, "action not found")
+		return
+	}
+	var body struct {
+		Description string `json:"description"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	p.actionDescriptions[action] = body.Description
+	writeJSON(w, http.StatusOK, map[string]string{"name": action, "description": body.Description})
+}
+
+func (s *Server) deleteProductAction(w http.ResponseWriter, r *http.Request, p *product) {
+	action := r.PathValue("action")
+	if !p.actions[action] {
+		writeError(w, http.StatusNotFound, "action not found")
+		return
+	}
+	delete(p.actions, action)
+	delete(p.actionDescriptions, action)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listRoles(w http.ResponseWriter, r *http.Request, p *product) {
+	roles := make([]*role, 0, len(p.roles))
+	for _, ro := range p.roles {
+		roles = append(roles, ro)
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i].name < roles[j].name })
+
+	resources := make([]any, 0, len(roles))
+	for _, ro := range roles {
+		resources = append(resources, ro.resource())
+	}
+	s.writePage(w, r, resources)
+}
+
+func (s *Server) postRole(w http.ResponseWriter, r *http.Request, p *product) {
+	var body struct {
+		Name          string `json:"name"`
+		Description   string `json:"description"`
+		DisplayName   string `json:"displayName"`
+		BindableLevel string `json:"bindableLevel"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Name == "" {
+		writeError(w, http.StatusBadRequest, "missing role name")
+		return
+	}
+	for _, ro := range p.roles {
+		if ro.name == body.Name {
+			writeError(w, http.StatusConflict, "role already exists")
+			return
+		}
+	}
+	created := &role{
+		timestamps:    newTimestamps(),
+		uid:           s.newUID(),
+		name:          body.Name,
+		description:   body.Description,
+		displayName:   body.DisplayName,
+		bindableLevel: body.BindableLevel,
+		actions:       map[string]bool{},
+	}
+	p.roles[created.uid] = created
+	writeJSON(w, http.StatusCreated, created.resource())
+}
+
+func (s *Server) patchRole(w http.ResponseWriter, r *http.Request, p *product) {
+	ro, ok := p.roles[r.PathValue("role")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "role not found")
+		return
+	}
+	var body struct {
+		Description   *string `json:"description"`
+		DisplayName   *string `json:"displayName"`
+		BindableLevel *string `json:"bindableLevel"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if body.Description != nil {
+		ro.description = *body.Description
+	}
+	if body.DisplayName != nil {
+		ro.displayName = *body.DisplayName
+	}
+	if body.BindableLevel != nil {
+		ro.bindableLevel = *body.BindableLevel
+	}
+	ro.touch()
+	writeJSON(w, http.StatusOK, ro.resource())
+}
+
+func (s *Server) deleteRole(w http.ResponseWriter, r *http.Request, p *product) {
+	uid := r.PathValue("role")
+	if _, ok := p.roles[uid]; !ok {
+		writeError(w, http.StatusNotFound, "role not found")
+		return
+	}
+	delete(p.roles, uid)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listRoleActions(w http.ResponseWriter, r *http.Request, p *product) {
+	ro, ok := p.roles[r.PathValue("role")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "role not found")
+		return
+	}
+	s.writePage(w, r, actionResources(ro.actions, nil))
+}
+
+func (s *Server) postRoleAction(w http.ResponseWriter, r *http.Request, p *product) {
+	ro, ok := p.roles[r.PathValue("role")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "role not found")
+		return
+	}
+	// the role level actions are posted as a bare JSON string
+	var action string
+	if err := json.NewDecoder(r.Body).Decode(&action); err != nil || action == "" {
+		writeError(w, http.StatusBadRequest, "missing action name")
+		return
+	}
+	if !p.actions[strings.TrimPrefix(action, r.PathValue("product")+".")] && !p.actions[action] {
+		writeError(w, http.StatusBadRequest, "action is not declared at product level")
+		return
+	}
+	ro.actions[action] = true
+	writeJSON(w, http.StatusCreated, map[string]string{"name": action})
+}
+
+func (s *Server) deleteRoleAction(w http.ResponseWriter, r *http.Request, p *product) {
+	ro, ok := p.roles[r.PathValue("role")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "role not found")
+		return
+	}
+	action := r.PathValue("action")
+	if !ro.actions[action] {
+		writeError(w, http.StatusNotFound, "action not found")
+		return
+	}
+	delete(ro.actions, action)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writePage writes the page of resources starting at the start query parameter, linking the next page
+func (s *Server) writePage(w http.ResponseWriter, r *http.Request, resources []any) {
+	size := len(resources)
+	if requested, err := strconv.Atoi(r.URL.Query().Get("pageSize")); err == nil && requested > 0 {
+		size = requested
+	}
+	if s.pageSize > 0 && s.pageSize < size {
+		size = s.pageSize
+	}
+	start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+	start = min(max(start, 0), len(resources))
+	end := min(start+size, len(resources))
+
+	page := map[string]any{"resources": resources[start:end]}
+	if end < len(resources) {
+		page["next"] = map[string]string{"start": strconv.Itoa(end)}
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+// newUID returns a new role UID. mu must be held.
+func (s *Server) newUID() string {
+	s.nextID++
+	return fmt.Sprintf("role-%d", s.nextID)
+}
+
+// newToken returns an opaque token carrying its expiry in a JWT exp claim, like the Account IAM tokens
+func newToken(id int, expiry time.Time) string {
+	encode := base64.RawURLEncoding.EncodeToString
+	header := encode([]byte(`{"alg":"none","typ":"JWT"}`))
+	claims := encode([]byte(fmt.Sprintf(`{"jti":"%d","exp":%d}`, id, expiry.Unix())))
+	return header + "." + claims + "." + encode([]byte(strconv.Itoa(id)))
+}
+
+func actionResources(actions map[string]bool, descriptions map[string]string) []any {
+	resources := make([]any, 0, len(actions))
+	for _, action := range sortedKeys(actions) {
+		resource := map[string]string{"name": action}
+		if description, ok := descriptions[action]; ok {
+			resource["description"] = description
+		}
+		resources = append(resources, resource)
+	}
+	return resources
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	writeJSON(w, statusCode, map[string]any{
+		"errors": []map[string]string{{"message": message}},
+		"status": statusCode,
+	})
+}
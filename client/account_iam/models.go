@@ -0,0 +1,33 @@
+package account_iam
+
+import "time"
+
+// Product is a product registered in Account IAM
+type Product struct {
+	// ID is the service ID identifying the product
+	ID          string     `json:"id"`
+	DisplayName string     `json:"displayName,omitempty"`
+	Description string     `json:"description,omitempty"`
+	CreatedAt   *time.Time `json:"createdAt,omitempty"`
+	UpdatedAt   *time.Time `json:"updatedAt,omitempty"`
+}
+
+// Role is a custom role of a product
+type Role struct {
+	// UID identifies the role in the paths of the API, the name is only unique within the product
+	UID           string     `json:"uid"`
+	Name          string     `json:"name"`
+	DisplayName   string     `json:"displayName,omitempty"`
+	Description   string     `json:"description"`
+	BindableLevel string     `json:"bindableLevel,omitempty"`
+	CreatedAt     *time.Time `json:"createdAt,omitempty"`
+	UpdatedAt     *time.Time `json:"updatedAt,omitempty"`
+}
+
+// Action is an action of a product or of a custom role. The role level actions carry the service ID prefix.
+type Action struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	CreatedAt   *time.Time `json:"createdAt,omitempty"`
+	UpdatedAt   *time.Time `json:"updatedAt,omitempty"`
+}
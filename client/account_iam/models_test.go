@@ -0,0 +1,105 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package account_iam
+
+import (
+	"context"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	operatorv1alpha1 "github.com/IBM/ibm-user-management-operator/api/v1alpha1"
+	"github.com/IBM/ibm-user-management-operator/client/account_iam/fake"
+	"github.com/IBM/ibm-user-management-operator/internal/retry"
+)
+
+var _ = Describe("Account IAM models", func() {
+	ctx := context.Background()
+
+	var fakeIAM *fake.Server
+	var client IAMClient
+
+	BeforeEach(func() {
+		fakeIAM = fake.NewServer()
+		DeferCleanup(fakeIAM.Close)
+		fakeIAM.RegisterProduct("product", fake.Product{
+			DisplayName:        "Product",
+			Description:        "The product",
+			Actions:            []string{"read"},
+			ActionDescriptions: map[string]string{"read": "Read the product"},
+			Roles: []fake.Role{
+				{UID: "viewer-uid", Name: "viewer", Description: "Viewer", BindableLevel: "SERVICE", Actions: []string{"product.read"}},
+			},
+		})
+
+		factory := NewClientFactory(&retry.Retry{}, FactoryOptions{
+			InsecureSkipVerify: true,
+			ServiceURL:         func(string) string { return fakeIAM.URL },
+		})
+		var err error
+		client, err = factory.ClientFor("accountiam-uid", ClientConfig{Namespace: "default", APIKey: "api-key"})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.GetToken(ctx, factory.TokenURL("default"))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should decode the product", func() {
+		product, statusCode, err := client.GetProductDetails(ctx, "product")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(statusCode).To(Equal(http.StatusOK))
+		Expect(product.ID).To(Equal("product"))
+		Expect(product.DisplayName).To(Equal("Product"))
+		Expect(product.Description).To(Equal("The product"))
+		Expect(product.CreatedAt).NotTo(BeNil())
+		Expect(product.UpdatedAt).NotTo(BeNil())
+	})
+
+	It("should report a missing product without decoding it", func() {
+		product, statusCode, err := client.GetProductDetails(ctx, "missing")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(statusCode).To(Equal(http.StatusNotFound))
+		Expect(product).To(BeNil())
+	})
+
+	It("should decode the roles and actions", func() {
+		roles, _, err := client.GetRoles(ctx, "product")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(roles).To(HaveLen(1))
+		Expect(roles[0].UID).To(Equal("viewer-uid"))
+		Expect(roles[0].Name).To(Equal("viewer"))
+		Expect(roles[0].BindableLevel).To(Equal("SERVICE"))
+		Expect(roles[0].CreatedAt).NotTo(BeNil())
+
+		actions, _, err := client.GetActionsProductLevel(ctx, "product")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(actions).To(Equal([]Action{{Name: "read", Description: "Read the product"}}))
+
+		actions, _, err = client.GetActionsRoleLevel(ctx, "product", "viewer-uid")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(actions).To(Equal([]Action{{Name: "product.read"}}))
+	})
+
+	It("should return the created custom role", func() {
+		role, statusCode, err := client.PostCustomRoles(ctx, operatorv1alpha1.V2CustomRoles{Name: "editor", Description: "Editor"}, "product")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(statusCode).To(Equal(http.StatusCreated))
+		Expect(role.UID).NotTo(BeEmpty())
+		Expect(role.Name).To(Equal("editor"))
+		Expect(role.BindableLevel).To(Equal(operatorv1alpha1.BindableLevelService))
+	})
+})
@@ -0,0 +1,203 @@
+package account_iam
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+
+	"github.com/IBM/ibm-user-management-operator/internal/retry"
+)
+
+const (
+	// pageStart is the query parameter of the first resource of a page
+	pageStart = "start"
+)
+
+// Page is a page of a listing of the Account IAM API
+type Page[T any] struct {
+	Resources []T `json:"resources"`
+	// Next links the following page, it is empty on the last page
+	Next *PageLink `json:"next,omitempty"`
+}
+
+// PageLink locates a page, either with its URL or with the start token of its first resource
+type PageLink struct {
+	Href  string `json:"href,omitempty"`
+	Start string `json:"start,omitempty"`
+}
+
+// Iterator walks the resources of a listing of the Account IAM API, fetching its pages as they are reached:
+//
+//	it := client.Roles(serviceID)
+//	for it.Next(ctx) {
+//		role := it.Value()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type Iterator[T any] struct {
+	client     *MCSPIAMClient
+	nextURL    string
+	visited    map[string]bool
+	page       []T
+	index      int
+	statusCode int
+	err        error
+}
+
+// NewIterator returns an Iterator over the listing of the Account IAM API at endpoint
+func NewIterator[T any](c *MCSPIAMClient, endpoint string) *Iterator[T] {
+	it := &Iterator[T]{
+		client:  c,
+		visited: map[string]bool{},
+		index:   -1,
+	}
+
+	reqURL, err := url.Parse(endpoint)
+	if err != nil {
+		it.err = retry.NewPermanentError(fmt.Errorf("failed to parse endpoint %s: %v", endpoint, err))
+		return it
+	}
+	query := reqURL.Query()
+	query.Set(pageSize, maxPageSize)
+	reqURL.RawQuery = query.Encode()
+	it.nextURL = reqURL.String()
+	return it
+}
+
+// Roles returns an Iterator over the custom roles of a product
+func (c *MCSPIAMClient) Roles(serviceID string) *Iterator[Role] {
+	return NewIterator[Role](c, c.BaseURL+"/"+serviceID+"/roles")
+}
+
+// ProductActions returns an Iterator over the actions of a product
+func (c *MCSPIAMClient) ProductActions(serviceID string) *Iterator[Action] {
+	return NewIterator[Action](c, c.BaseURL+"/"+serviceID+"/actions")
+}
+
+// RoleActions returns an Iterator over the actions of a custom role of a product
+func (c *MCSPIAMClient) RoleActions(serviceID string, roleUID string) *Iterator[Action] {
+	return NewIterator[Action](c, c.BaseURL+"/"+serviceID+"/roles/"+roleUID+"/actions")
+}
+
+// Next advances to the next resource, fetching the following page when the current one is exhausted.
+// It returns false at the end of the listing or on error.
+func (it *Iterator[T]) Next(ctx context.Context) bool {
+	for {
+		if it.index+1 < len(it.page) {
+			it.index++
+			return true
+		}
+		if it.err != nil || it.nextURL == "" {
+			return false
+		}
+		it.fetch(ctx)
+	}
+}
+
+// Value returns the current resource
+func (it *Iterator[T]) Value() T {
+	return it.page[it.index]
+}
+
+// Err returns the error which stopped the iteration, if any
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// StatusCode returns the status code of the last page request
+func (it *Iterator[T]) StatusCode() int {
+	return it.statusCode
+}
+
+// All drains the iterator and returns all the resources of the listing
+func (it *Iterator[T]) All(ctx context.Context) ([]T, error) {
+	var all []T
+	for it.Next(ctx) {
+		all = append(all, it.Value())
+	}
+	return all, it.Err()
+}
+
+// fetch requests the next page and locates the one following it
+func (it *Iterator[T]) fetch(ctx context.Context) {
+	pageURL := it.nextURL
+	it.nextURL = ""
+	if it.visited[pageURL] {
+		it.err = fmt.Errorf("page %s was already listed, the listing loops", pageURL)
+		return
+	}
+	it.visited[pageURL] = true
+
+	var page Page[T]
+	var finalErr error
+	//retryhandler to avoid crashing the operator when IAM is down
+	retryErr := it.client.retry.RetryHandler(ctx, func() error {
+		page = Page[T]{}
+		response, sc, err := it.client.Get(ctx, pageURL)
+		it.statusCode = sc
+
+		if err != nil {
+			log.Info("GET failed, will retry if allowed", "url", pageURL)
+			finalErr = fmt.Errorf("failed to do GET request: %v", err)
+			return finalErr
+		}
+		defer response.Body.Close()
+
+		if sc >= 400 && sc < 500 {
+			finalErr = fmt.Errorf("GET returned client error: %d", sc)
+			return retry.NewPermanentError(finalErr)
+		}
+		if sc >= 500 {
+			finalErr = fmt.Errorf("GET returned server error: %d", sc)
+			return finalErr
+		}
+
+		if err := json.NewDecoder(response.Body).Decode(&page); err != nil && err != io.EOF {
+			log.Error(err, "failed to decode page", "url", pageURL)
+			finalErr = err
+			return err
+		}
+		return nil
+	})
+	if retryErr != nil {
+		log.Error(retryErr, "GET failed", "url", pageURL)
+		it.err = retryErr
+		return
+	}
+
+	it.page = page.Resources
+	it.index = -1
+	if page.Next != nil {
+		nextURL, err := nextPageURL(pageURL, page.Next)
+		if err != nil {
+			it.err = retry.NewPermanentError(err)
+			return
+		}
+		it.nextURL = nextURL
+	}
+}
+
+// nextPageURL resolves the link to the following page against the URL of the current page
+func nextPageURL(pageURL string, link *PageLink) (string, error) {
+	current, err := url.Parse(pageURL)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case link.Href != "":
+		href, err := url.Parse(link.Href)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse the link to the next page %s: %v", link.Href, err)
+		}
+		return current.ResolveReference(href).String(), nil
+	case link.Start != "":
+		query := current.Query()
+		query.Set(pageStart, link.Start)
+		current.RawQuery = query.Encode()
+		return current.String(), nil
+	}
+	return "", nil
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package account_iam
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	operatorv1alpha1 "github.com/IBM/ibm-user-management-operator/api/v1alpha1"
+	"github.com/IBM/ibm-user-management-operator/internal/retry"
+)
+
+// pagedServer serves count resources built by resource, in pages of size linked with link
+func pagedServer(count, size int, resource func(i int) any, link func(r *http.Request, start int) *PageLink) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start, _ := strconv.Atoi(r.URL.Query().Get(pageStart))
+		page := Page[any]{Resources: []any{}}
+		for i := start; i < count && i < start+size; i++ {
+			page.Resources = append(page.Resources, resource(i))
+		}
+		if start+size < count {
+			page.Next = link(r, start+size)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		Expect(json.NewEncoder(w).Encode(page)).To(Succeed())
+	}))
+}
+
+var _ = Describe("Account IAM listings", func() {
+	ctx := context.Background()
+
+	newClient := func(server *httptest.Server) *MCSPIAMClient {
+		iamClient, err := NewMCSPIAMClient(server.URL, "api-key", nil, 0, &retry.Retry{})
+		Expect(err).NotTo(HaveOccurred())
+		return iamClient.(*MCSPIAMClient)
+	}
+
+	It("should follow the href links of the custom roles pages", func() {
+		server := pagedServer(250, 100, func(i int) any {
+			return Role{Name: fmt.Sprintf("role-%d", i), UID: fmt.Sprintf("uid-%d", i)}
+		}, func(r *http.Request, start int) *PageLink {
+			return &PageLink{Href: fmt.Sprintf("%s?%s=%d&%s=%s", r.URL.Path, pageStart, start, pageSize, maxPageSize)}
+		})
+		defer server.Close()
+
+		instance := &operatorv1alpha1.RoleActionConfig{Spec: operatorv1alpha1.RoleActionConfigSpec{ServiceID: "product"}}
+		UIDs, statusCode, err := newClient(server).GetUID(ctx, instance)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(statusCode).To(Equal(http.StatusOK))
+		Expect(UIDs).To(HaveLen(250))
+		Expect(UIDs).To(HaveKeyWithValue("role-249", "uid-249"))
+	})
+
+	It("should follow the start tokens of the actions pages", func() {
+		server := pagedServer(201, 100, func(i int) any {
+			return Action{Name: fmt.Sprintf("product.action-%d", i)}
+		}, func(_ *http.Request, start int) *PageLink {
+			return &PageLink{Start: strconv.Itoa(start)}
+		})
+		defer server.Close()
+
+		actions, statusCode, err := newClient(server).GetActionsProductLevel(ctx, "product")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(statusCode).To(Equal(http.StatusOK))
+		Expect(actions).To(HaveLen(201))
+		Expect(actions[200].Name).To(Equal("product.action-200"))
+	})
+
+	It("should iterate over the role actions page by page", func() {
+		var requests int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			page := Page[Action]{Resources: []Action{{Name: "product.read"}}}
+			if r.URL.Query().Get(pageStart) == "" {
+				page.Next = &PageLink{Start: "1"}
+			}
+			Expect(json.NewEncoder(w).Encode(page)).To(Succeed())
+		}))
+		defer server.Close()
+
+		it := newClient(server).RoleActions("product", "role-uid")
+		Expect(it.Next(ctx)).To(BeTrue())
+		Expect(it.Value().Name).To(Equal("product.read"))
+		Expect(requests).To(Equal(1))
+		Expect(it.Next(ctx)).To(BeTrue())
+		Expect(requests).To(Equal(2))
+		Expect(it.Next(ctx)).To(BeFalse())
+		Expect(it.Err()).NotTo(HaveOccurred())
+	})
+
+	It("should stop on a listing linking back to a listed page", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			page := Page[Action]{
+				Resources: []Action{{Name: "product.read"}},
+				Next:      &PageLink{Href: r.URL.String()},
+			}
+			Expect(json.NewEncoder(w).Encode(page)).To(Succeed())
+		}))
+		defer server.Close()
+
+		_, _, err := newClient(server).GetActionsProductLevel(ctx, "product")
+		Expect(err).To(MatchError(ContainSubstring("already listed")))
+	})
+
+	It("should report the status code of a missing product", func() {
+		server := httptest.NewServer(http.NotFoundHandler())
+		defer server.Close()
+
+		_, statusCode, err := newClient(server).GetActionsRoleLevel(ctx, "product", "role-uid")
+		Expect(err).To(HaveOccurred())
+		Expect(retry.IsPermanentError(err)).To(BeTrue())
+		Expect(statusCode).To(Equal(http.StatusNotFound))
+	})
+})
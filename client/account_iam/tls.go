@@ -0,0 +1,36 @@
+package account_iam
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+)
+
+// ServiceName returns the name the certificate of the Account IAM service deployed in a namespace is issued for
+func ServiceName(namespace string) string {
+	return fmt.Sprintf("account-iam.%s.svc", namespace)
+}
+
+// NewTLSConfig returns the TLS configuration verifying the Account IAM service deployed in namespace
+// against the CA certificates of caBundle. With insecureSkipVerify, the certificate is not verified
+// at all, which is only meant for debugging.
+func NewTLSConfig(namespace string, caBundle []byte, insecureSkipVerify bool) (*tls.Config, error) {
+	if insecureSkipVerify {
+		return &tls.Config{InsecureSkipVerify: true}, nil // #nosec G402 explicit opt-in for debugging
+	}
+
+	if len(caBundle) == 0 {
+		return nil, errors.New("no CA certificate to verify the Account IAM service with")
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caBundle) {
+		return nil, errors.New("no valid PEM certificate in the CA bundle of the Account IAM service")
+	}
+
+	return &tls.Config{
+		RootCAs:    pool,
+		ServerName: ServiceName(namespace),
+		MinVersion: tls.VersionTLS12,
+	}, nil
+}
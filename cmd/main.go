@@ -30,6 +30,7 @@ import (
 	certmgrv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 	routev1 "github.com/openshift/api/route/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -45,6 +46,7 @@ import (
 	operatorv1alpha1 "github.com/IBM/ibm-user-management-operator/api/v1alpha1"
 	"github.com/IBM/ibm-user-management-operator/client/account_iam"
 	"github.com/IBM/ibm-user-management-operator/internal/controller"
+	"github.com/IBM/ibm-user-management-operator/internal/iamsync"
 	"github.com/IBM/ibm-user-management-operator/internal/resources/images"
 	"github.com/IBM/ibm-user-management-operator/internal/retry"
 
@@ -82,6 +84,10 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var insecureSkipTLSVerify bool
+	var iamRequestTimeout time.Duration
+	var iamSyncTimeout time.Duration
+	var maxActionDeletions string
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
@@ -91,6 +97,15 @@ func main() {
 		"If set the metrics endpoint is served securely")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.BoolVar(&insecureSkipTLSVerify, "account-iam-insecure-skip-tls-verify", false,
+		"If set, the certificate of the Account IAM service is not verified. Only meant for debugging")
+	flag.DurationVar(&iamRequestTimeout, "account-iam-request-timeout", 30*time.Second,
+		"The timeout of each request sent to Account IAM. Zero means no timeout")
+	flag.DurationVar(&iamSyncTimeout, "account-iam-sync-timeout", 5*time.Minute,
+		"The deadline of all the requests, retries included, sent to Account IAM by one RoleActionConfig reconcile. Zero means no deadline")
+	flag.StringVar(&maxActionDeletions, "account-iam-max-action-deletions", "50%",
+		"The count, or the percentage, of product level actions, of role level actions and of custom roles a RoleActionConfig sync may remove from Account IAM "+
+			"before the pruning is blocked until acknowledged. Empty means no limit")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -99,6 +114,16 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	var safeguard iamsync.Safeguard
+	if maxActionDeletions != "" {
+		maxDeletions := intstr.Parse(maxActionDeletions)
+		if limit, err := intstr.GetScaledValueFromIntOrPercent(&maxDeletions, 100, false); err != nil || limit < 0 {
+			setupLog.Error(err, "invalid --account-iam-max-action-deletions, expected a count or a percentage", "value", maxActionDeletions)
+			os.Exit(1)
+		}
+		safeguard.MaxDeletions = &maxDeletions
+	}
+
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
 	// prevent from being vulnerable to the HTTP/2 Stream Cancelation and
@@ -162,11 +187,28 @@ func main() {
 		os.Exit(1)
 	}
 
+	if insecureSkipTLSVerify {
+		setupLog.Info("WARNING: the certificate of the Account IAM service is not verified")
+	}
+	retryHandler := &retry.Retry{
+		BackoffInterval:   BackoffInterval,
+		BackoffMultiplier: BackoffMultiplier,
+		BackoffMaxRetries: BackoffMaxRetries,
+	}
+
+	// The endpoints and API key of each AccountIAM are only known once its operand is deployed,
+	// the factory builds their clients on demand
+	iamClients := account_iam.NewClientFactory(retryHandler, account_iam.FactoryOptions{
+		InsecureSkipVerify: insecureSkipTLSVerify,
+		RequestTimeout:     iamRequestTimeout,
+	})
+
 	if err = (&controller.AccountIAMReconciler{
-		Client:   mgr.GetClient(),
-		Scheme:   mgr.GetScheme(),
-		Config:   mgr.GetConfig(),
-		Recorder: mgr.GetEventRecorderFor("account-iam-controller"),
+		Client:     mgr.GetClient(),
+		Scheme:     mgr.GetScheme(),
+		Config:     mgr.GetConfig(),
+		Recorder:   mgr.GetEventRecorderFor("account-iam-controller"),
+		IAMClients: iamClients,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AccountIAM")
 		os.Exit(1)
@@ -178,28 +220,22 @@ func main() {
 		}
 	}
 
-	retryHandler := &retry.Retry{
-		BackoffInterval:   BackoffInterval,
-		BackoffMultiplier: BackoffMultiplier,
-		BackoffMaxRetries: BackoffMaxRetries,
-	}
-
-	IAMProductRolesEndpoint := "" // empty because AccountIAM operand must be created first to know where operand namespace is
-	IAMAPIKey := ""               // API key is empty to start because need to fetch from mcsp-im-integration-details secret
-
-	iamClient, err := account_iam.NewMCSPIAMClient(IAMProductRolesEndpoint, IAMAPIKey, retryHandler)
-	if err != nil {
-		setupLog.Error(err, "failed to create IAM client")
-		os.Exit(1)
-	}
 	if err = (&controller.RoleActionConfigReconciler{
-		Client:    mgr.GetClient(),
-		Scheme:    mgr.GetScheme(),
-		APIClient: iamClient,
+		Client:      mgr.GetClient(),
+		Scheme:      mgr.GetScheme(),
+		IAMClients:  iamClients,
+		SyncTimeout: iamSyncTimeout,
+		Safeguard:   safeguard,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "RoleActionConfig")
 		os.Exit(1)
 	}
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = (&operatorv1alpha1.RoleActionConfig{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "RoleActionConfig")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -23,6 +23,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 	"text/template"
 	"time"
@@ -34,6 +35,7 @@ import (
 	networkingv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -46,6 +48,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	operatorv1alpha1 "github.com/IBM/ibm-user-management-operator/api/v1alpha1"
+	"github.com/IBM/ibm-user-management-operator/client/account_iam"
 	"github.com/IBM/ibm-user-management-operator/internal/controller/utils"
 	"github.com/IBM/ibm-user-management-operator/internal/resources"
 	"github.com/IBM/ibm-user-management-operator/internal/resources/images"
@@ -60,16 +63,20 @@ type AccountIAMReconciler struct {
 	Scheme   *runtime.Scheme
 	Config   *rest.Config
 	Recorder record.EventRecorder
+	// IAMClients is the factory shared with the RoleActionConfig controller, its client of a deleted AccountIAM is evicted
+	IAMClients account_iam.ClientFactory
 }
 
 // ReconcileContext holds all the data needed during reconciliation
 type ReconcileContext struct {
 	Instance        *operatorv1alpha1.AccountIAM
+	Spec            operatorv1alpha1.AccountIAMSpec
 	BootstrapData   BootstrapSecret
 	IntegrationData IntegrationConfig
 	UIData          UIBootstrapTemplate
 	RouteData       RouteParams
 	RedisCRData     RedisCRParams
+	DatabaseData    DatabaseParams
 	Host            string
 	WLPClientID     string
 }
@@ -101,19 +108,40 @@ type IntegrationConfig struct {
 	AccountIAMURL           string
 	AccountIAMConsoleURL    string
 	AccountIAMNamespace     string
+	APIKeyName              string
 	EncryptionKeys          string
 	CurrentEncryptionKeyNum string
 }
 
 // RouteParams holds the parameters for the Route CR
 type RouteParams struct {
+	Host   string
 	CAcert string
 }
 
 // RedisCRParams holds the parameters for the Redis CR
 type RedisCRParams struct {
-	RedisCRSize    int
-	RedisCRVersion string
+	RedisCRSize         int32
+	RedisCRVersion      string
+	RedisCRScaleConfig  string
+	RedisCRPersistence  bool
+	RedisCRStorageClass string
+	RedisCRStorageSize  string
+}
+
+// DatabaseParams holds the connection parameters of the Account IAM database
+type DatabaseParams struct {
+	DBHost            string
+	DBPort            int32
+	DBName            string
+	DBSchema          string
+	DBUser            string
+	DBSSLMode         string
+	DBCASecret        string
+	DBCAKey           string
+	DBPasswordSecret  string
+	DBPasswordKey     string
+	DBSuperuserSecret string
 }
 
 type UIBootstrapTemplate struct {
@@ -157,7 +185,7 @@ type UIBootstrapTemplate struct {
 //+kubebuilder:rbac:groups=operator.ibm.com,namespace="placeholder",resources=accountiams,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=operator.ibm.com,namespace="placeholder",resources=accountiams/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=operator.ibm.com,namespace="placeholder",resources=accountiams/finalizers,verbs=update
-//+kubebuilder:rbac:groups=operator.ibm.com,namespace="placeholder",resources=operandrequests,verbs=get;list;watch;create
+//+kubebuilder:rbac:groups=operator.ibm.com,namespace="placeholder",resources=operandrequests,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=redis.ibm.com,namespace="placeholder",resources=rediscps,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=route.openshift.io,namespace="placeholder",resources=routes,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=route.openshift.io,namespace="placeholder",resources=routes/custom-host,verbs=get;list;watch;create;update;patch;delete
@@ -197,6 +225,10 @@ func (r *AccountIAMReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, err
 	}
 
+	if !instance.DeletionTimestamp.IsZero() {
+		return r.finalize(ctx, instance)
+	}
+
 	// Create a copy of the status to detect changes
 	originalStatus := instance.Status.DeepCopy()
 
@@ -220,30 +252,103 @@ func (r *AccountIAMReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	return ctrl.Result{}, nil
 }
 
-// reconcilePhases executes all reconciliation phases in order
+// reconcilePhase is a reconciliation phase and the condition reporting its result
+type reconcilePhase struct {
+	conditionType string
+	run           func(context.Context, *ReconcileContext) error
+}
+
+// reconcilePhases executes all reconciliation phases in order and records
+// the result of each phase in the status conditions
 func (r *AccountIAMReconciler) reconcilePhases(ctx context.Context, reconcileCtx *ReconcileContext) error {
-	phases := []func(context.Context, *ReconcileContext) error{
-		r.initializeReconcileContext,
-		r.reconcilePrerequisites,
-		r.reconcileOperandResourcesPhase,
-		r.reconcileIMConfiguration,
-		r.reconcileUIPhase,
+	if err := r.initializeReconcileContext(ctx, reconcileCtx); err != nil {
+		return err
 	}
 
-	for _, phase := range phases {
-		if err := phase(ctx, reconcileCtx); err != nil {
+	phases := []reconcilePhase{
+		{operatorv1alpha1.ConditionTypePrerequisitesReady, r.reconcilePrerequisites},
+		{operatorv1alpha1.ConditionTypeOperandResourcesReady, r.reconcileOperandResourcesPhase},
+		{operatorv1alpha1.ConditionTypeIMConfigured, r.reconcileIMConfiguration},
+		{operatorv1alpha1.ConditionTypeUIReady, r.reconcileUIPhase},
+	}
+
+	instance := reconcileCtx.Instance
+	instance.Status.ObservedGeneration = instance.Generation
+
+	for i, phase := range phases {
+		if err := phase.run(ctx, reconcileCtx); err != nil {
+			setCondition(instance, phase.conditionType, metav1.ConditionFalse, operatorv1alpha1.ConditionReasonFailed, err.Error())
+			for _, pending := range phases[i+1:] {
+				setCondition(instance, pending.conditionType, metav1.ConditionUnknown, operatorv1alpha1.ConditionReasonPending,
+					utils.Concat("Waiting for ", phase.conditionType))
+			}
+
+			message := utils.Concat(phase.conditionType, ": ", err.Error())
+			setCondition(instance, operatorv1alpha1.ConditionTypeReady, metav1.ConditionFalse, operatorv1alpha1.ConditionReasonFailed, message)
+			setCondition(instance, operatorv1alpha1.ConditionTypeProgressing, metav1.ConditionTrue, operatorv1alpha1.ConditionReasonReconciling, message)
+			setCondition(instance, operatorv1alpha1.ConditionTypeDegraded, metav1.ConditionTrue, operatorv1alpha1.ConditionReasonFailed, message)
 			return err
 		}
+		setCondition(instance, phase.conditionType, metav1.ConditionTrue, operatorv1alpha1.ConditionReasonReconciled, "")
 	}
+
+	setCondition(instance, operatorv1alpha1.ConditionTypeReady, metav1.ConditionTrue, operatorv1alpha1.ConditionReasonReconciled, "All phases reconciled")
+	setCondition(instance, operatorv1alpha1.ConditionTypeProgressing, metav1.ConditionFalse, operatorv1alpha1.ConditionReasonReconciled, "")
+	setCondition(instance, operatorv1alpha1.ConditionTypeDegraded, metav1.ConditionFalse, operatorv1alpha1.ConditionReasonReconciled, "")
 	return nil
 }
 
+// setCondition sets a status condition of the AccountIAM for its current generation
+func setCondition(instance *operatorv1alpha1.AccountIAM, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: instance.Generation,
+	})
+}
+
 // initializeReconcileContext initializes the reconcile context with basic data
 func (r *AccountIAMReconciler) initializeReconcileContext(ctx context.Context, reconcileCtx *ReconcileContext) error {
+	// Resolve the effective spec, leaving the instance untouched
+	spec := reconcileCtx.Instance.Spec.DeepCopy()
+	spec.SetDefaults()
+	reconcileCtx.Spec = *spec
+
 	// Initialize Redis CR data
 	reconcileCtx.RedisCRData = RedisCRParams{
-		RedisCRSize:    3,
-		RedisCRVersion: "1.2.8",
+		RedisCRSize:        spec.Redis.Size,
+		RedisCRVersion:     spec.Redis.Version,
+		RedisCRScaleConfig: spec.Redis.ScaleConfig,
+	}
+	if storage := spec.Redis.Storage; storage != nil {
+		reconcileCtx.RedisCRData.RedisCRPersistence = true
+		reconcileCtx.RedisCRData.RedisCRStorageClass = storage.StorageClassName
+		if storage.Size != nil {
+			reconcileCtx.RedisCRData.RedisCRStorageSize = storage.Size.String()
+		}
+	}
+
+	// Initialize database connection data
+	reconcileCtx.DatabaseData = DatabaseParams{
+		DBHost:            spec.Database.Host,
+		DBPort:            spec.Database.Port,
+		DBName:            spec.Database.Name,
+		DBSchema:          spec.Database.Schema,
+		DBUser:            spec.Database.User,
+		DBSSLMode:         spec.Database.SSLMode,
+		DBPasswordSecret:  resources.BootstrapSecret,
+		DBPasswordKey:     resources.PGPasswordKey,
+		DBSuperuserSecret: spec.Database.SuperuserSecretRef.Name,
+	}
+	if ref := spec.Database.PasswordSecretRef; ref != nil {
+		reconcileCtx.DatabaseData.DBPasswordSecret = ref.Name
+		reconcileCtx.DatabaseData.DBPasswordKey = ref.Key
+	}
+	if ref := spec.Database.CASecretRef; ref != nil {
+		reconcileCtx.DatabaseData.DBCASecret = ref.Name
+		reconcileCtx.DatabaseData.DBCAKey = ref.Key
 	}
 
 	return nil
@@ -290,13 +395,29 @@ func (r *AccountIAMReconciler) updateStatus(ctx context.Context, instance *opera
 
 func (r *AccountIAMReconciler) verifyPrereq(ctx context.Context, reconcileCtx *ReconcileContext) error {
 	instance := reconcileCtx.Instance
-	operatorNames := []string{resources.RedisOperator, resources.IMPackage}
+	redisManaged := reconcileCtx.Spec.Redis.Mode == operatorv1alpha1.RedisModeManaged
+
+	operatorNames := []string{resources.IMPackage}
+	if redisManaged {
+		operatorNames = []string{resources.RedisOperator, resources.IMPackage}
+	} else {
+		// Remove the Redis of a former Managed mode while its operator is still requested to process the deletion
+		if err := r.cleanupManagedRedis(ctx, instance.Namespace); err != nil {
+			klog.Errorf("Failed to clean up the managed Redis: %v", err)
+			return err
+		}
+	}
 
 	// Request IM operator and wait for their status
 	if err := r.createOperandRequest(ctx, instance, resources.UserMgmtOpreq, operatorNames); err != nil {
 		return err
 	}
 
+	// From here on the operator changes resources outside of the owner references of the AccountIAM
+	if err := r.ensureFinalizer(ctx, instance); err != nil {
+		return err
+	}
+
 	if err := r.createOperandRBAC(ctx, instance); err != nil {
 		return err
 	}
@@ -307,9 +428,14 @@ func (r *AccountIAMReconciler) verifyPrereq(ctx context.Context, reconcileCtx *R
 	}
 
 	// Create Redis CR and wait for it to be ready
-	if err := r.createRedisCR(ctx, reconcileCtx); err != nil {
-		klog.Errorf("Failed to create Redis CR: %v", err)
-		return err
+	if redisManaged {
+		if err := r.createRedisCR(ctx, reconcileCtx); err != nil {
+			klog.Errorf("Failed to create Redis CR: %v", err)
+			return err
+		}
+	} else {
+		klog.Infof("Redis mode is %s, skipping Redis CR %s", reconcileCtx.Spec.Redis.Mode, resources.Rediscp)
+		instance.Status.Redis = operatorv1alpha1.RedisStatus{Mode: reconcileCtx.Spec.Redis.Mode}
 	}
 
 	if err := utils.WaitForOperandReady(ctx, r.Client, resources.UserMgmtOpreq, instance.Namespace); err != nil {
@@ -325,8 +451,8 @@ func (r *AccountIAMReconciler) verifyPrereq(ctx context.Context, reconcileCtx *R
 	}
 
 	// Get cp-console route after operand request is ready
-	klog.Info("Getting cp-console route")
-	host, err := utils.GetHost(ctx, r.Client, "cp-console", instance.Namespace)
+	klog.Infof("Getting %s route", reconcileCtx.Spec.Routing.ConsoleRoute)
+	host, err := utils.GetHost(ctx, r.Client, reconcileCtx.Spec.Routing.ConsoleRoute, instance.Namespace)
 	if err != nil {
 		return err
 	}
@@ -362,18 +488,35 @@ func (r *AccountIAMReconciler) verifyPrereq(ctx context.Context, reconcileCtx *R
 	return nil
 }
 
+// ensureFinalizer adds the cleanup finalizer to the AccountIAM without losing the status built by the current reconcile
+func (r *AccountIAMReconciler) ensureFinalizer(ctx context.Context, instance *operatorv1alpha1.AccountIAM) error {
+	if controllerutil.ContainsFinalizer(instance, resources.AccountIAMFinalizer) {
+		return nil
+	}
+
+	klog.Infof("Adding finalizer %s to AccountIAM %s/%s", resources.AccountIAMFinalizer, instance.Namespace, instance.Name)
+	updated := instance.DeepCopy()
+	controllerutil.AddFinalizer(updated, resources.AccountIAMFinalizer)
+	if err := r.Update(ctx, updated); err != nil {
+		return err
+	}
+	instance.Finalizers = updated.Finalizers
+	instance.ResourceVersion = updated.ResourceVersion
+	return nil
+}
+
 // CreateOperandRequest creates an OperandRequest resource
 func (r *AccountIAMReconciler) createOperandRequest(ctx context.Context, instance *operatorv1alpha1.AccountIAM, name string, operandNames []string) error {
+	var operands []odlm.Operand
+	for _, name := range operandNames {
+		operands = append(operands, odlm.Operand{Name: name})
+	}
+
 	operandRequest := &odlm.OperandRequest{}
 	if err := r.Get(ctx, client.ObjectKey{Name: name, Namespace: instance.Namespace}, operandRequest); err != nil {
 		if !k8serrors.IsNotFound(err) {
 			return err
 		}
-		var operands []odlm.Operand
-		for _, name := range operandNames {
-			operands = append(operands, odlm.Operand{Name: name})
-		}
-
 		klog.Infof("Creating OperandRequest %s in namespace %s", name, instance.Namespace)
 
 		operandRequest = &odlm.OperandRequest{
@@ -415,6 +558,13 @@ func (r *AccountIAMReconciler) createOperandRequest(ctx context.Context, instanc
 			needsUpdate = true
 		}
 
+		// Keep the requested operands in sync, e.g. when the Redis mode changes
+		if len(operandRequest.Spec.Requests) > 0 && !operandNamesEqual(operandRequest.Spec.Requests[0].Operands, operandNames) {
+			klog.Infof("Updating operands of OperandRequest %s to %v", operandRequest.Name, operandNames)
+			operandRequest.Spec.Requests[0].Operands = operands
+			needsUpdate = true
+		}
+
 		if needsUpdate {
 			if err := r.Update(ctx, operandRequest); err != nil {
 				return err
@@ -425,6 +575,19 @@ func (r *AccountIAMReconciler) createOperandRequest(ctx context.Context, instanc
 	return nil
 }
 
+// operandNamesEqual reports whether the operands carry exactly the given names in order
+func operandNamesEqual(operands []odlm.Operand, names []string) bool {
+	if len(operands) != len(names) {
+		return false
+	}
+	for i, operand := range operands {
+		if operand.Name != names[i] {
+			return false
+		}
+	}
+	return true
+}
+
 func (r *AccountIAMReconciler) createRedisCR(ctx context.Context, reconcileCtx *ReconcileContext) error {
 	instance := reconcileCtx.Instance
 
@@ -465,10 +628,53 @@ func (r *AccountIAMReconciler) createRedisCR(ctx context.Context, reconcileCtx *
 		}
 	}
 
+	// Report the settings applied to the Redis CR
+	redisData := reconcileCtx.RedisCRData
+	instance.Status.Redis = operatorv1alpha1.RedisStatus{
+		Mode:             operatorv1alpha1.RedisModeManaged,
+		Size:             redisData.RedisCRSize,
+		Version:          redisData.RedisCRVersion,
+		ScaleConfig:      redisData.RedisCRScaleConfig,
+		StorageClassName: redisData.RedisCRStorageClass,
+		StorageSize:      redisData.RedisCRStorageSize,
+	}
+
 	// Wait for Redis CR to be ready
 	return utils.WaitForRediscp(ctx, r.Client, instance.Namespace, resources.Rediscp, resources.RedisAPIGroup, resources.RedisKind, resources.Version, resources.StatusCompleted)
 }
 
+// cleanupManagedRedis deletes the Redis CR, its certificates and their secrets left by the Managed Redis mode
+func (r *AccountIAMReconciler) cleanupManagedRedis(ctx context.Context, ns string) error {
+	newObject := func(apiVersion, kind, name string) client.Object {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(apiVersion)
+		obj.SetKind(kind)
+		obj.SetName(name)
+		obj.SetNamespace(ns)
+		return obj
+	}
+	objects := []client.Object{
+		newObject(utils.Concat(resources.RedisAPIGroup, "/", resources.Version), resources.RedisKind, resources.Rediscp),
+		newObject("cert-manager.io/v1", "Certificate", resources.RedisSVCCert),
+		newObject("cert-manager.io/v1", "Certificate", resources.RedisCACert),
+		newObject("cert-manager.io/v1", "Issuer", resources.RedisCAIssuer),
+		newObject("v1", "Secret", resources.Rediscp),
+		newObject("v1", "Secret", resources.RedisSVCCert),
+		newObject("v1", "Secret", resources.RedisCACert),
+	}
+
+	for _, obj := range objects {
+		if err := r.Delete(ctx, obj); err != nil {
+			if k8serrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+				continue
+			}
+			return err
+		}
+		klog.Infof("Deleted %s %s of the managed Redis in namespace %s", obj.GetObjectKind().GroupVersionKind().Kind, obj.GetName(), ns)
+	}
+	return nil
+}
+
 // InitBootstrapData initializes BootstrapData with default values
 func (r *AccountIAMReconciler) initBootstrapData(ctx context.Context, ns string, pg []byte) (*corev1.Secret, error) {
 
@@ -520,11 +726,23 @@ func (r *AccountIAMReconciler) initBootstrapData(ctx context.Context, ns string,
 func (r *AccountIAMReconciler) initMCSPData(reconcileCtx *ReconcileContext) error {
 	klog.Infof("Initializing MCSP Data")
 	instance := reconcileCtx.Instance
+	spec := reconcileCtx.Spec
 	host := reconcileCtx.Host
 	ns := instance.Namespace
 
-	accountIAMHost := strings.Replace(host, "cp-console", "account-iam", 1)
-	accountIAMUIHost := strings.Replace(host, "cp-console", "account-iam-console", 1)
+	accountIAMHost := spec.Routing.APIHostname
+	if accountIAMHost == "" {
+		accountIAMHost = strings.Replace(host, spec.Routing.ConsoleRoute, "account-iam", 1)
+	}
+	accountIAMUIHost := spec.Routing.ConsoleHostname
+	if accountIAMUIHost == "" {
+		accountIAMUIHost = strings.Replace(host, spec.Routing.ConsoleRoute, "account-iam-console", 1)
+	}
+
+	issuerURL := spec.Integration.OIDCIssuerURL
+	if issuerURL == "" {
+		issuerURL = utils.Concat("https://", host, "/idprovider/v1/auth")
+	}
 
 	existingSecret := &corev1.Secret{}
 	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: resources.AccountIAMDBSecret, Namespace: ns}, existingSecret)
@@ -554,14 +772,15 @@ func (r *AccountIAMReconciler) initMCSPData(reconcileCtx *ReconcileContext) erro
 	}
 
 	reconcileCtx.IntegrationData = IntegrationConfig{
-		AccountName:             "default-account",
-		ServiceName:             "default-service",
-		ServiceIDName:           "default-serviceid",
-		SubscriptionName:        "default-subscription",
-		DiscoveryEndpoint:       utils.Concat("https://", host, "/idprovider/v1/auth/.well-known/openid-configuration"),
-		DefaultIDPValue:         utils.Concat("https://", host, "/idprovider/v1/auth"),
-		GlobalAccountIDP:        utils.Concat("https://", host, "/idprovider/v1/auth"),
+		AccountName:             spec.AccountIAM.AccountName,
+		ServiceName:             spec.AccountIAM.ServiceName,
+		ServiceIDName:           spec.AccountIAM.ServiceIDName,
+		SubscriptionName:        spec.AccountIAM.SubscriptionName,
+		DiscoveryEndpoint:       utils.Concat(issuerURL, "/.well-known/openid-configuration"),
+		DefaultIDPValue:         issuerURL,
+		GlobalAccountIDP:        issuerURL,
 		AccountIAMNamespace:     ns,
+		APIKeyName:              spec.Integration.APIKeyName,
 		IMURL:                   utils.Concat("https://", host),
 		AccountIAMURL:           utils.Concat("https://", accountIAMHost),
 		AccountIAMConsoleURL:    utils.Concat("https://", accountIAMUIHost),
@@ -661,8 +880,10 @@ func (r *AccountIAMReconciler) createOperandRBAC(ctx context.Context, instance *
 func (r *AccountIAMReconciler) reconcileOperandResources(ctx context.Context, reconcileCtx *ReconcileContext) error {
 	instance := reconcileCtx.Instance
 
-	// Create DB Bootstrap Job
-	if err := r.createDBBootstrapJob(ctx, instance); err != nil {
+	// Create DB Bootstrap Job unless the database is pre-provisioned
+	if reconcileCtx.Spec.Database.PreProvisioned {
+		klog.Infof("Database %s is pre-provisioned, skipping job %s", reconcileCtx.Spec.Database.Name, resources.CreateDBJob)
+	} else if err := r.createDBBootstrapJob(ctx, reconcileCtx); err != nil {
 		return err
 	}
 
@@ -671,6 +892,11 @@ func (r *AccountIAMReconciler) reconcileOperandResources(ctx context.Context, re
 		return err
 	}
 
+	// Resolve the database credentials and CA bundle
+	if err := r.prepareDatabaseData(ctx, reconcileCtx); err != nil {
+		return err
+	}
+
 	// Create MCSP secrets
 	if err := r.createMCSPSecrets(ctx, reconcileCtx); err != nil {
 		return err
@@ -682,7 +908,7 @@ func (r *AccountIAMReconciler) reconcileOperandResources(ctx context.Context, re
 	}
 
 	// Create Account IAM resources
-	if err := r.createAccountIAMResources(ctx, instance); err != nil {
+	if err := r.createAccountIAMResources(ctx, reconcileCtx); err != nil {
 		return err
 	}
 
@@ -701,19 +927,26 @@ func (r *AccountIAMReconciler) reconcileOperandResources(ctx context.Context, re
 }
 
 // createDBBootstrapJob creates the database bootstrap job
-func (r *AccountIAMReconciler) createDBBootstrapJob(ctx context.Context, instance *operatorv1alpha1.AccountIAM) error {
+func (r *AccountIAMReconciler) createDBBootstrapJob(ctx context.Context, reconcileCtx *ReconcileContext) error {
 	klog.Infof("Applying DB Bootstrap Job")
-	object := &unstructured.Unstructured{}
-	resource := images.ReplaceInYAML(yamls.DB_BOOTSTRAP_JOB)
-	manifest := []byte(resource)
-	if err := yaml.Unmarshal(manifest, object); err != nil {
-		return err
-	}
-	object.SetNamespace(instance.Namespace)
-	if err := controllerutil.SetControllerReference(instance, object, r.Scheme); err != nil {
-		return err
+	return r.injectData(ctx, reconcileCtx.Instance, []string{yamls.DB_BOOTSTRAP_JOB}, reconcileCtx.DatabaseData)
+}
+
+// prepareDatabaseData reads the database password from the referenced secret
+func (r *AccountIAMReconciler) prepareDatabaseData(ctx context.Context, reconcileCtx *ReconcileContext) error {
+	ns := reconcileCtx.Instance.Namespace
+	database := reconcileCtx.Spec.Database
+
+	if ref := database.PasswordSecretRef; ref != nil {
+		password, err := utils.GetSecretData(ctx, r.Client, ref.Name, ns, ref.Key)
+		if err != nil {
+			klog.Errorf("Failed to get database password from secret %s in namespace %s: %v", ref.Name, ns, err)
+			return err
+		}
+		reconcileCtx.BootstrapData.PGPassword = base64.StdEncoding.EncodeToString([]byte(password))
 	}
-	return r.createOrUpdate(ctx, object)
+
+	return nil
 }
 
 // prepareBootstrapData gets WLP client ID and prepares bootstrap data
@@ -756,7 +989,7 @@ func (r *AccountIAMReconciler) prepareBootstrapData(ctx context.Context, reconci
 // createMCSPSecrets creates MCSP secrets with injected data
 func (r *AccountIAMReconciler) createMCSPSecrets(ctx context.Context, reconcileCtx *ReconcileContext) error {
 	klog.Infof("Creating MCSP secrets")
-	return r.injectData(ctx, reconcileCtx.Instance, append(yamls.APP_SECRETS, yamls.IM_INTEGRATION_YAMLS...), reconcileCtx.BootstrapData, reconcileCtx.IntegrationData)
+	return r.injectData(ctx, reconcileCtx.Instance, append(yamls.APP_SECRETS, yamls.IM_INTEGRATION_YAMLS...), reconcileCtx.BootstrapData, reconcileCtx.IntegrationData, reconcileCtx.DatabaseData)
 }
 
 // createStaticManifests creates static YAML manifests
@@ -766,13 +999,17 @@ func (r *AccountIAMReconciler) createStaticManifests(ctx context.Context, instan
 }
 
 // createAccountIAMResources creates Account IAM resources
-func (r *AccountIAMReconciler) createAccountIAMResources(ctx context.Context, instance *operatorv1alpha1.AccountIAM) error {
+func (r *AccountIAMReconciler) createAccountIAMResources(ctx context.Context, reconcileCtx *ReconcileContext) error {
 	klog.Infof("Creating Account IAM yamls")
+	instance := reconcileCtx.Instance
 	yamlsToProcess := make([]string, len(yamls.ACCOUNT_IAM_RES))
 	for i, v := range yamls.ACCOUNT_IAM_RES {
 		yamlsToProcess[i] = strings.ReplaceAll(v, "${NAMESPACE}", instance.Namespace)
 	}
-	return r.createResourcesFromYAMLs(ctx, instance, yamlsToProcess)
+	spec := reconcileCtx.Spec.AccountIAM
+	return r.createResourcesFromYAMLs(ctx, instance, yamlsToProcess, func(object *unstructured.Unstructured) error {
+		return utils.SetDeploymentWorkload(object, *spec.Replicas, spec.Resources)
+	})
 }
 
 // createAccountIAMRoutes creates Account IAM routes with CA certificate data
@@ -787,6 +1024,7 @@ func (r *AccountIAMReconciler) createAccountIAMRoutes(ctx context.Context, recon
 	}
 
 	reconcileCtx.RouteData = RouteParams{
+		Host:   reconcileCtx.Spec.Routing.APIHostname,
 		CAcert: utils.IndentCert(caCRT, 6),
 	}
 
@@ -812,8 +1050,9 @@ func (r *AccountIAMReconciler) configureAndWaitForIssuer(ctx context.Context, re
 	return nil
 }
 
-// createResourcesFromYAMLs is a helper function to create resources from YAML manifests
-func (r *AccountIAMReconciler) createResourcesFromYAMLs(ctx context.Context, instance *operatorv1alpha1.AccountIAM, yamls []string) error {
+// createResourcesFromYAMLs is a helper function to create resources from YAML manifests.
+// The optional mutators are applied to every object before it is created or updated.
+func (r *AccountIAMReconciler) createResourcesFromYAMLs(ctx context.Context, instance *operatorv1alpha1.AccountIAM, yamls []string, mutators ...func(*unstructured.Unstructured) error) error {
 	for _, v := range yamls {
 		object := &unstructured.Unstructured{}
 
@@ -826,6 +1065,11 @@ func (r *AccountIAMReconciler) createResourcesFromYAMLs(ctx context.Context, ins
 			return err
 		}
 		object.SetNamespace(instance.Namespace)
+		for _, mutate := range mutators {
+			if err := mutate(object); err != nil {
+				return err
+			}
+		}
 		if err := controllerutil.SetControllerReference(instance, object, r.Scheme); err != nil {
 			return err
 		}
@@ -911,6 +1155,10 @@ func (r *AccountIAMReconciler) configureIssuerViaCS(ctx context.Context, integra
 	// Track if we need to update the CR
 	needsUpdate := false
 
+	// Track the oidcIssuerURL set before the change, to be restored when the AccountIAM is deleted
+	previousURL := ""
+	issuerChanged := false
+
 	// If ibm-im-operator service not found, append it
 	if imServiceIndex == -1 {
 		klog.Infof("Adding ibm-im-operator service to CommonService CR %s/%s", utils.GetOperatorNamespace(), "common-service")
@@ -926,6 +1174,7 @@ func (r *AccountIAMReconciler) configureIssuerViaCS(ctx context.Context, integra
 		}
 		services = append(services, imService)
 		needsUpdate = true
+		issuerChanged = true
 	} else {
 		// Update existing service
 		serviceMap := services[imServiceIndex].(map[string]interface{})
@@ -958,6 +1207,8 @@ func (r *AccountIAMReconciler) configureIssuerViaCS(ctx context.Context, integra
 			klog.Infof("Updating oidcIssuerURL from %s to %s", currentURL, integrationData.DefaultIDPValue)
 			config["oidcIssuerURL"] = integrationData.DefaultIDPValue
 			needsUpdate = true
+			previousURL = currentURL
+			issuerChanged = true
 		} else {
 			klog.Infof("CommonService CR %s/%s already has the desired oidcIssuerURL: %s", utils.GetOperatorNamespace(), "common-service", currentURL)
 		}
@@ -969,6 +1220,10 @@ func (r *AccountIAMReconciler) configureIssuerViaCS(ctx context.Context, integra
 		services[imServiceIndex] = serviceMap
 	}
 
+	if recordIssuer(commonService, previousURL, integrationData.DefaultIDPValue, issuerChanged) {
+		needsUpdate = true
+	}
+
 	// Only update if changes were made
 	if needsUpdate {
 		if err := unstructured.SetNestedSlice(commonService.Object, services, "spec", "services"); err != nil {
@@ -984,6 +1239,32 @@ func (r *AccountIAMReconciler) configureIssuerViaCS(ctx context.Context, integra
 	return nil
 }
 
+// recordIssuer annotates the CommonService CR with the oidcIssuerURL it had before the operator first
+// changed it, and with the one the operator configured. An empty previous value means the oidcIssuerURL
+// was not set. It reports whether the annotations changed.
+func recordIssuer(commonService *unstructured.Unstructured, previousURL, configuredURL string, issuerChanged bool) bool {
+	annotations := commonService.GetAnnotations()
+	_, recorded := annotations[resources.PreviousOIDCIssuerAnnotation]
+	if !issuerChanged && !recorded {
+		return false
+	}
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	changed := false
+	if !recorded {
+		annotations[resources.PreviousOIDCIssuerAnnotation] = previousURL
+		changed = true
+	}
+	if configured, ok := annotations[resources.ConfiguredOIDCIssuerAnnotation]; !ok || configured != configuredURL {
+		annotations[resources.ConfiguredOIDCIssuerAnnotation] = configuredURL
+		changed = true
+	}
+	commonService.SetAnnotations(annotations)
+	return changed
+}
+
 func (r *AccountIAMReconciler) waitForIssuerinCM(ctx context.Context, ns string, integrationData IntegrationConfig) error {
 	timeout := time.After(5 * time.Minute)
 	ticker := time.NewTicker(10 * time.Second)
@@ -1082,12 +1363,16 @@ func (r *AccountIAMReconciler) reconcileUI(ctx context.Context, reconcileCtx *Re
 	}
 
 	klog.Infof("Creating static yamls for UI")
-	return r.createResourcesFromYAMLs(ctx, reconcileCtx.Instance, yamls.StaticYamlsUI)
+	spec := reconcileCtx.Spec.UI
+	return r.createResourcesFromYAMLs(ctx, reconcileCtx.Instance, yamls.StaticYamlsUI, func(object *unstructured.Unstructured) error {
+		return utils.SetDeploymentWorkload(object, *spec.Replicas, spec.Resources)
+	})
 }
 
 func (r *AccountIAMReconciler) initUIBootstrapData(ctx context.Context, reconcileCtx *ReconcileContext) error {
 	klog.Infof("Initializing UI Bootstrap Data")
 	instance := reconcileCtx.Instance
+	spec := reconcileCtx.Spec
 
 	clusterInfo := &corev1.ConfigMap{}
 	if err := r.Get(ctx, types.NamespacedName{Namespace: instance.Namespace, Name: "ibmcloud-cluster-info"}, clusterInfo); err != nil {
@@ -1111,31 +1396,11 @@ func (r *AccountIAMReconciler) initUIBootstrapData(ctx context.Context, reconcil
 		return err
 	}
 
-	// Get the Redis URL SSL
-	redisURlssl, err := utils.GetSecretData(ctx, r.Client, resources.Rediscp, instance.Namespace, resources.RedisURLssl)
-	if err != nil {
-		klog.Errorf("Failed to get secret %s in namespace %s: %v", resources.Rediscp, instance.Namespace, err)
-		return err
-	}
-	redisHostname, redisPort, err := utils.GetRedisInfo(redisURlssl)
-	if err != nil {
-		fmt.Println("Error:", err)
-		return err
-	}
-
-	redisPassword, err := utils.GetSecretData(ctx, r.Client, resources.Rediscp, instance.Namespace, resources.RedisPassword)
-	if err != nil {
-		klog.Errorf("Failed to get redis password from secret %s in namespace %s: %v", resources.Rediscp, instance.Namespace, err)
-		return err
-	}
-
-	// get Redis Certificate Authority
-	caCRT, err := utils.GetSecretData(ctx, r.Client, resources.RedisCACert, instance.Namespace, resources.CAKey)
+	// Get the Redis connection
+	redisConn, err := r.getRedisConnection(ctx, reconcileCtx)
 	if err != nil {
-		klog.Errorf("Failed to get ca.crt from secret %s in namespace %s", resources.CSCASecret, instance.Namespace)
 		return err
 	}
-	caCRT = base64.StdEncoding.EncodeToString([]byte(caCRT))
 
 	SessionSecret, err := utils.RandStrings(48)
 	if err != nil {
@@ -1151,32 +1416,121 @@ func (r *AccountIAMReconciler) initUIBootstrapData(ctx context.Context, reconcil
 		return err
 	}
 
+	consoleHostname := spec.Routing.ConsoleHostname
+	if consoleHostname == "" {
+		consoleHostname = utils.Concat("account-iam-console-", instance.Namespace, ".", domain)
+	}
+	apiHostname := spec.Routing.APIHostname
+	if apiHostname == "" {
+		apiHostname = utils.Concat("account-iam-", instance.Namespace, ".", domain)
+	}
+
 	reconcileCtx.UIData = UIBootstrapTemplate{
-		Hostname:                   utils.Concat("account-iam-console-", instance.Namespace, ".", domain),
-		InstanceManagementHostname: utils.Concat("account-iam-console-", instance.Namespace, ".", domain),
+		Hostname:                   consoleHostname,
+		InstanceManagementHostname: consoleHostname,
 		ClientID:                   string(decodedClientID),
 		ClientSecret:               string(decodedClientSecret),
 		IAMGlobalAPIKey:            string(apiKey),
-		RedisHost:                  redisHostname,
-		RedisPort:                  redisPort,
-		RedisPassword:              redisPassword,
-		RedisCA:                    caCRT,
+		RedisHost:                  redisConn.Host,
+		RedisPort:                  redisConn.Port,
+		RedisPassword:              redisConn.Password,
+		RedisCA:                    redisConn.CA,
+		DisableRedis:               redisConn.Disabled,
 		SessionSecret:              string(SessionSecret[0]),
-		DeploymentCloud:            "IBM_CLOUD",
-		IAMAPI:                     utils.Concat("https://account-iam-", instance.Namespace, ".", domain),
-		NodeEnv:                    "production",
+		DeploymentCloud:            spec.UI.DeploymentCloud,
+		IAMAPI:                     utils.Concat("https://", apiHostname),
+		NodeEnv:                    spec.UI.NodeEnv,
 		CertDir:                    "../../security",
-		ConfigEnv:                  "dev",
+		ConfigEnv:                  spec.UI.ConfigEnv,
 		IssuerBaseURL:              utils.Concat(cpconsole, "/idprovider/v1/auth"),
 		IMIDMgmt:                   cpconsole,
 		CSIDPURL:                   utils.Concat(cpconsole, "/common-nav/identity-access/realms"),
-		DefaultAccount:             "default-account",
-		DefaultInstance:            "default-service",
+		DefaultAccount:             spec.AccountIAM.AccountName,
+		DefaultInstance:            spec.AccountIAM.ServiceName,
 	}
 
 	return nil
 }
 
+// redisConnection holds the Redis settings rendered into the UI secret
+type redisConnection struct {
+	Host     string
+	Port     string
+	Password string
+	CA       string
+	Disabled string
+}
+
+// getRedisConnection resolves the Redis connection of the UI for the configured Redis mode
+func (r *AccountIAMReconciler) getRedisConnection(ctx context.Context, reconcileCtx *ReconcileContext) (*redisConnection, error) {
+	ns := reconcileCtx.Instance.Namespace
+	redisSpec := reconcileCtx.Spec.Redis
+
+	switch redisSpec.Mode {
+	case operatorv1alpha1.RedisModeDisabled:
+		return &redisConnection{Disabled: "true"}, nil
+
+	case operatorv1alpha1.RedisModeExternal:
+		external := redisSpec.External
+		if external == nil || external.Host == "" {
+			return nil, errors.New("spec.redis.external.host is required when spec.redis.mode is External")
+		}
+		conn := &redisConnection{
+			Host: external.Host,
+			Port: strconv.Itoa(int(external.Port)),
+		}
+		if ref := external.PasswordSecretRef; ref != nil {
+			password, err := utils.GetSecretData(ctx, r.Client, ref.Name, ns, ref.Key)
+			if err != nil {
+				klog.Errorf("Failed to get redis password from secret %s in namespace %s: %v", ref.Name, ns, err)
+				return nil, err
+			}
+			conn.Password = password
+		}
+		if ref := external.CASecretRef; ref != nil {
+			caCRT, err := utils.GetSecretData(ctx, r.Client, ref.Name, ns, ref.Key)
+			if err != nil {
+				klog.Errorf("Failed to get redis CA from secret %s in namespace %s: %v", ref.Name, ns, err)
+				return nil, err
+			}
+			conn.CA = base64.StdEncoding.EncodeToString([]byte(caCRT))
+		}
+		return conn, nil
+	}
+
+	// Get the Redis URL SSL
+	redisURlssl, err := utils.GetSecretData(ctx, r.Client, resources.Rediscp, ns, resources.RedisURLssl)
+	if err != nil {
+		klog.Errorf("Failed to get secret %s in namespace %s: %v", resources.Rediscp, ns, err)
+		return nil, err
+	}
+	redisHostname, redisPort, err := utils.GetRedisInfo(redisURlssl)
+	if err != nil {
+		klog.Errorf("Failed to parse redis url: %v", err)
+		return nil, err
+	}
+
+	redisPassword, err := utils.GetSecretData(ctx, r.Client, resources.Rediscp, ns, resources.RedisPassword)
+	if err != nil {
+		klog.Errorf("Failed to get redis password from secret %s in namespace %s: %v", resources.Rediscp, ns, err)
+		return nil, err
+	}
+
+	// get Redis Certificate Authority
+	caCRT, err := utils.GetSecretData(ctx, r.Client, resources.RedisCACert, ns, resources.CAKey)
+	if err != nil {
+		klog.Errorf("Failed to get ca.crt from secret %s in namespace %s", resources.RedisCACert, ns)
+		return nil, err
+	}
+
+	return &redisConnection{
+		Host:     redisHostname,
+		Port:     redisPort,
+		Password: redisPassword,
+		CA:       base64.StdEncoding.EncodeToString([]byte(caCRT)),
+	}, nil
+}
+
 // -------------- Reconcile UI functions done --------------
 
 func (r *AccountIAMReconciler) createOrUpdate(ctx context.Context, obj *unstructured.Unstructured) error {
@@ -1281,11 +1635,13 @@ func (r *AccountIAMReconciler) updateManagedResourcesStatus(ctx context.Context,
 	var managedResources []odlm.ResourceStatus
 	allResourcesReady := true
 
-	// Check Redis status
-	redisResource, redisReady := utils.GetRedisResourceStatus(ctx, r.Client, instance.Namespace)
-	managedResources = append(managedResources, redisResource)
-	if !redisReady {
-		allResourcesReady = false
+	// Check Redis status when the operator manages it
+	if mode := instance.Spec.Redis.Mode; mode == "" || mode == operatorv1alpha1.RedisModeManaged {
+		redisResource, redisReady := utils.GetRedisResourceStatus(ctx, r.Client, instance.Namespace)
+		managedResources = append(managedResources, redisResource)
+		if !redisReady {
+			allResourcesReady = false
+		}
 	}
 
 	// Check OperandRequest status
@@ -1296,7 +1652,10 @@ func (r *AccountIAMReconciler) updateManagedResourcesStatus(ctx context.Context,
 	}
 
 	// Check job statuses
-	jobsToCheck := []string{resources.CreateDBJob, resources.DBMigrationJob, resources.IMConfigJob}
+	jobsToCheck := []string{resources.DBMigrationJob, resources.IMConfigJob}
+	if !instance.Spec.Database.PreProvisioned {
+		jobsToCheck = append([]string{resources.CreateDBJob}, jobsToCheck...)
+	}
 	for _, jobName := range jobsToCheck {
 		jobResource, jobReady := utils.GetJobStatus(ctx, r.Client, jobName, instance.Namespace)
 		managedResources = append(managedResources, jobResource)
@@ -1366,6 +1725,163 @@ func (r *AccountIAMReconciler) updateManagedResourcesStatus(ctx context.Context,
 
 }
 
+// -------------- finalizer helper functions --------------
+
+// finalize cleans up the resources the AccountIAM leaves outside of its owner references and releases its finalizer
+func (r *AccountIAMReconciler) finalize(ctx context.Context, instance *operatorv1alpha1.AccountIAM) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(instance, resources.AccountIAMFinalizer) {
+		return ctrl.Result{}, nil
+	}
+	klog.Infof("Cleaning up AccountIAM %s/%s", instance.Namespace, instance.Name)
+
+	spec := instance.Spec.DeepCopy()
+	spec.SetDefaults()
+
+	if err := r.revertIssuerInCS(ctx); err != nil {
+		klog.Errorf("Failed to revert OIDC issuer URL in CommonService CR: %v", err)
+		return ctrl.Result{}, err
+	}
+
+	if err := r.cleanupOperandRequest(ctx, instance, spec.Uninstall.OperandRequestPolicy); err != nil {
+		klog.Errorf("Failed to clean up OperandRequest %s: %v", resources.UserMgmtOpreq, err)
+		return ctrl.Result{}, err
+	}
+
+	if err := r.cleanupSecrets(ctx, instance.Namespace, spec.Uninstall.SecretPolicy); err != nil {
+		klog.Errorf("Failed to clean up secrets: %v", err)
+		return ctrl.Result{}, err
+	}
+
+	if r.IAMClients != nil {
+		r.IAMClients.Evict(string(instance.UID))
+	}
+
+	controllerutil.RemoveFinalizer(instance, resources.AccountIAMFinalizer)
+	if err := r.Update(ctx, instance); err != nil {
+		return ctrl.Result{}, err
+	}
+	klog.Infof("Cleanup completed for AccountIAM %s/%s", instance.Namespace, instance.Name)
+	return ctrl.Result{}, nil
+}
+
+// revertIssuerInCS restores the oidcIssuerURL of ibm-im-operator recorded in the CommonService CR
+// before the operator first changed it
+func (r *AccountIAMReconciler) revertIssuerInCS(ctx context.Context) error {
+	commonService := &unstructured.Unstructured{}
+	commonService.SetAPIVersion("operator.ibm.com/v3")
+	commonService.SetKind("CommonService")
+
+	if err := r.Get(ctx, client.ObjectKey{Name: "common-service", Namespace: utils.GetOperatorNamespace()}, commonService); err != nil {
+		if k8serrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			klog.Infof("CommonService CR %s/%s not found, skipping OIDC issuer URL revert", utils.GetOperatorNamespace(), "common-service")
+			return nil
+		}
+		return err
+	}
+
+	changed, err := revertIssuer(commonService)
+	if err != nil || !changed {
+		return err
+	}
+	return r.Update(ctx, commonService)
+}
+
+// revertIssuer restores in the CommonService CR the oidcIssuerURL recorded before the operator first changed it,
+// unless the oidcIssuerURL was changed since the operator configured it, and drops the annotations recording it.
+// It reports whether the CommonService CR changed.
+func revertIssuer(commonService *unstructured.Unstructured) (bool, error) {
+	annotations := commonService.GetAnnotations()
+	previousURL, recorded := annotations[resources.PreviousOIDCIssuerAnnotation]
+	if !recorded {
+		klog.Infof("CommonService CR %s/%s has no recorded OIDC issuer URL, nothing to revert", utils.GetOperatorNamespace(), "common-service")
+		return false, nil
+	}
+	configuredURL, configured := annotations[resources.ConfiguredOIDCIssuerAnnotation]
+
+	services, _, err := unstructured.NestedSlice(commonService.Object, "spec", "services")
+	if err != nil {
+		return false, fmt.Errorf("failed to get services from CommonService CR %s/%s: %v", utils.GetOperatorNamespace(), "common-service", err)
+	}
+
+	for i, service := range services {
+		serviceMap, ok := service.(map[string]interface{})
+		if !ok || serviceMap["name"] != "ibm-im-operator" {
+			continue
+		}
+		currentURL, _, _ := unstructured.NestedString(serviceMap, "spec", "authentication", "config", "oidcIssuerURL")
+		if !configured || currentURL != configuredURL {
+			klog.Infof("oidcIssuerURL in CommonService CR %s/%s changed since it was configured, keeping %s",
+				utils.GetOperatorNamespace(), "common-service", currentURL)
+			continue
+		}
+		if previousURL == "" {
+			klog.Infof("Removing oidcIssuerURL from CommonService CR %s/%s", utils.GetOperatorNamespace(), "common-service")
+			unstructured.RemoveNestedField(serviceMap, "spec", "authentication", "config", "oidcIssuerURL")
+		} else {
+			klog.Infof("Reverting oidcIssuerURL in CommonService CR %s/%s to %s", utils.GetOperatorNamespace(), "common-service", previousURL)
+			if err := unstructured.SetNestedField(serviceMap, previousURL, "spec", "authentication", "config", "oidcIssuerURL"); err != nil {
+				return false, err
+			}
+		}
+		services[i] = serviceMap
+	}
+
+	if err := unstructured.SetNestedSlice(commonService.Object, services, "spec", "services"); err != nil {
+		return false, err
+	}
+	delete(annotations, resources.PreviousOIDCIssuerAnnotation)
+	delete(annotations, resources.ConfiguredOIDCIssuerAnnotation)
+	commonService.SetAnnotations(annotations)
+	return true, nil
+}
+
+// cleanupOperandRequest deletes the OperandRequest of the AccountIAM, or releases it from the
+// owner references of the AccountIAM so that it is not garbage collected when it is retained
+func (r *AccountIAMReconciler) cleanupOperandRequest(ctx context.Context, instance *operatorv1alpha1.AccountIAM, policy string) error {
+	operandRequest := &odlm.OperandRequest{}
+	if err := r.Get(ctx, types.NamespacedName{Name: resources.UserMgmtOpreq, Namespace: instance.Namespace}, operandRequest); err != nil {
+		if k8serrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil
+		}
+		return err
+	}
+
+	if policy == operatorv1alpha1.CleanupPolicyRetain {
+		var ownerRefs []metav1.OwnerReference
+		for _, ref := range operandRequest.OwnerReferences {
+			if ref.UID != instance.UID {
+				ownerRefs = append(ownerRefs, ref)
+			}
+		}
+		if len(ownerRefs) == len(operandRequest.OwnerReferences) {
+			return nil
+		}
+		klog.Infof("Retaining OperandRequest %s in namespace %s", operandRequest.Name, operandRequest.Namespace)
+		operandRequest.OwnerReferences = ownerRefs
+		return r.Update(ctx, operandRequest)
+	}
+
+	klog.Infof("Deleting OperandRequest %s in namespace %s", operandRequest.Name, operandRequest.Namespace)
+	return client.IgnoreNotFound(r.Delete(ctx, operandRequest))
+}
+
+// cleanupSecrets deletes the secrets created without an owner when the policy is Delete
+func (r *AccountIAMReconciler) cleanupSecrets(ctx context.Context, ns string, policy string) error {
+	if policy != operatorv1alpha1.CleanupPolicyDelete {
+		klog.Infof("Retaining secret %s in namespace %s", resources.BootstrapSecret, ns)
+		return nil
+	}
+
+	for _, name := range []string{resources.BootstrapSecret} {
+		klog.Infof("Deleting secret %s in namespace %s", name, ns)
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns}}
+		if err := r.Delete(ctx, secret); err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *AccountIAMReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
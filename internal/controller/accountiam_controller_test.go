@@ -17,13 +17,21 @@ limitations under the License.
 package controller
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
+	"text/template"
 	"time"
 
+	"github.com/ghodss/yaml"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
@@ -32,7 +40,11 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	operatorv1alpha1 "github.com/IBM/ibm-user-management-operator/api/v1alpha1"
+	"github.com/IBM/ibm-user-management-operator/client/account_iam"
 	"github.com/IBM/ibm-user-management-operator/internal/controller/testutils"
+	"github.com/IBM/ibm-user-management-operator/internal/resources"
+	"github.com/IBM/ibm-user-management-operator/internal/resources/yamls"
+	"github.com/IBM/ibm-user-management-operator/internal/retry"
 )
 
 var _ = Describe("AccountIAM Controller", func() {
@@ -562,6 +574,206 @@ var _ = Describe("AccountIAM Controller", func() {
 
 				k8sClient.Delete(ctx, accountIAM)
 			})
+
+			It("should resolve Redis settings from the spec", func() {
+				By("Initializing reconcile context with default Redis settings")
+				reconcileCtx := &ReconcileContext{
+					Instance: &operatorv1alpha1.AccountIAM{},
+				}
+				Expect(reconciler.initializeReconcileContext(ctx, reconcileCtx)).To(Succeed())
+				Expect(reconcileCtx.RedisCRData).To(Equal(RedisCRParams{
+					RedisCRSize:        operatorv1alpha1.DefaultRedisSize,
+					RedisCRVersion:     operatorv1alpha1.DefaultRedisVersion,
+					RedisCRScaleConfig: operatorv1alpha1.DefaultRedisScaleConfig,
+				}))
+
+				By("Initializing reconcile context with custom Redis settings")
+				storageSize := resource.MustParse("2Gi")
+				reconcileCtx = &ReconcileContext{
+					Instance: &operatorv1alpha1.AccountIAM{
+						Spec: operatorv1alpha1.AccountIAMSpec{
+							Redis: operatorv1alpha1.RedisSpec{
+								Size:        1,
+								Version:     "1.3.0",
+								ScaleConfig: "small",
+								Storage: &operatorv1alpha1.RedisStorageSpec{
+									StorageClassName: "fast",
+									Size:             &storageSize,
+								},
+							},
+						},
+					},
+				}
+				Expect(reconciler.initializeReconcileContext(ctx, reconcileCtx)).To(Succeed())
+				Expect(reconcileCtx.RedisCRData).To(Equal(RedisCRParams{
+					RedisCRSize:         1,
+					RedisCRVersion:      "1.3.0",
+					RedisCRScaleConfig:  "small",
+					RedisCRPersistence:  true,
+					RedisCRStorageClass: "fast",
+					RedisCRStorageSize:  "2Gi",
+				}))
+			})
+		})
+
+		Context("Redis Connection Functions", func() {
+			It("should disable Redis in the Disabled mode", func() {
+				reconcileCtx := &ReconcileContext{
+					Instance: &operatorv1alpha1.AccountIAM{
+						ObjectMeta: metav1.ObjectMeta{Namespace: AccountIAMNamespace},
+						Spec: operatorv1alpha1.AccountIAMSpec{
+							Redis: operatorv1alpha1.RedisSpec{Mode: operatorv1alpha1.RedisModeDisabled},
+						},
+					},
+				}
+				Expect(reconciler.initializeReconcileContext(ctx, reconcileCtx)).To(Succeed())
+
+				conn, err := reconciler.getRedisConnection(ctx, reconcileCtx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(conn.Disabled).To(Equal("true"))
+				Expect(conn.Host).To(BeEmpty())
+			})
+
+			It("should read the external Redis connection from the referenced secrets", func() {
+				By("Creating the external Redis secret")
+				redisSecret := &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "external-redis",
+						Namespace: AccountIAMNamespace,
+					},
+					Data: map[string][]byte{
+						"password": []byte("redis-password"),
+						"ca.crt":   []byte("redis-ca"),
+					},
+				}
+				Expect(k8sClient.Create(ctx, redisSecret)).To(Succeed())
+
+				reconcileCtx := &ReconcileContext{
+					Instance: &operatorv1alpha1.AccountIAM{
+						ObjectMeta: metav1.ObjectMeta{Namespace: AccountIAMNamespace},
+						Spec: operatorv1alpha1.AccountIAMSpec{
+							Redis: operatorv1alpha1.RedisSpec{
+								Mode: operatorv1alpha1.RedisModeExternal,
+								External: &operatorv1alpha1.ExternalRedisSpec{
+									Host: "redis.example.com",
+									PasswordSecretRef: &corev1.SecretKeySelector{
+										LocalObjectReference: corev1.LocalObjectReference{Name: "external-redis"},
+										Key:                  "password",
+									},
+									CASecretRef: &corev1.SecretKeySelector{
+										LocalObjectReference: corev1.LocalObjectReference{Name: "external-redis"},
+										Key:                  "ca.crt",
+									},
+								},
+							},
+						},
+					},
+				}
+				Expect(reconciler.initializeReconcileContext(ctx, reconcileCtx)).To(Succeed())
+
+				conn, err := reconciler.getRedisConnection(ctx, reconcileCtx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(conn.Host).To(Equal("redis.example.com"))
+				Expect(conn.Port).To(Equal("6379"))
+				Expect(conn.Password).To(Equal("redis-password"))
+				Expect(conn.CA).To(Equal(base64.StdEncoding.EncodeToString([]byte("redis-ca"))))
+				Expect(conn.Disabled).To(BeEmpty())
+
+				Expect(k8sClient.Delete(ctx, redisSecret)).To(Succeed())
+			})
+
+			It("should clean up the managed Redis when leaving the Managed mode", func() {
+				By("Creating the secrets of the managed Redis")
+				for _, name := range []string{resources.Rediscp, resources.RedisSVCCert, resources.RedisCACert} {
+					Expect(k8sClient.Create(ctx, &corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: AccountIAMNamespace},
+					})).To(Succeed())
+				}
+
+				By("Switching to the Disabled mode")
+				Expect(reconciler.cleanupManagedRedis(ctx, AccountIAMNamespace)).To(Succeed())
+				for _, name := range []string{resources.Rediscp, resources.RedisSVCCert, resources.RedisCACert} {
+					err := k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: AccountIAMNamespace}, &corev1.Secret{})
+					Expect(errors.IsNotFound(err)).To(BeTrue())
+				}
+
+				By("Cleaning up again once nothing is left")
+				Expect(reconciler.cleanupManagedRedis(ctx, AccountIAMNamespace)).To(Succeed())
+			})
+
+			It("should require a host in the External mode", func() {
+				reconcileCtx := &ReconcileContext{
+					Instance: &operatorv1alpha1.AccountIAM{
+						Spec: operatorv1alpha1.AccountIAMSpec{
+							Redis: operatorv1alpha1.RedisSpec{Mode: operatorv1alpha1.RedisModeExternal},
+						},
+					},
+				}
+				Expect(reconciler.initializeReconcileContext(ctx, reconcileCtx)).To(Succeed())
+
+				_, err := reconciler.getRedisConnection(ctx, reconcileCtx)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		Context("Database Functions", func() {
+			It("should resolve the external database settings from the spec", func() {
+				By("Creating the database secret")
+				dbSecret := &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "external-postgres",
+						Namespace: AccountIAMNamespace,
+					},
+					Data: map[string][]byte{
+						"password": []byte("db-password"),
+						"ca.crt":   []byte("db-ca"),
+					},
+				}
+				Expect(k8sClient.Create(ctx, dbSecret)).To(Succeed())
+
+				reconcileCtx := &ReconcileContext{
+					Instance: &operatorv1alpha1.AccountIAM{
+						ObjectMeta: metav1.ObjectMeta{Namespace: AccountIAMNamespace},
+						Spec: operatorv1alpha1.AccountIAMSpec{
+							Database: operatorv1alpha1.DatabaseSpec{
+								Host:    "postgres.example.com",
+								SSLMode: "verify-full",
+								PasswordSecretRef: &corev1.SecretKeySelector{
+									LocalObjectReference: corev1.LocalObjectReference{Name: "external-postgres"},
+									Key:                  "password",
+								},
+								CASecretRef: &corev1.SecretKeySelector{
+									LocalObjectReference: corev1.LocalObjectReference{Name: "external-postgres"},
+									Key:                  "ca.crt",
+								},
+								PreProvisioned: true,
+							},
+						},
+					},
+				}
+				Expect(reconciler.initializeReconcileContext(ctx, reconcileCtx)).To(Succeed())
+				Expect(reconcileCtx.DatabaseData.DBHost).To(Equal("postgres.example.com"))
+				Expect(reconcileCtx.DatabaseData.DBPort).To(Equal(operatorv1alpha1.DefaultDatabasePort))
+				Expect(reconcileCtx.DatabaseData.DBPasswordSecret).To(Equal("external-postgres"))
+				Expect(reconcileCtx.DatabaseData.DBSuperuserSecret).To(Equal(operatorv1alpha1.DefaultDatabaseSuperuser))
+
+				Expect(reconciler.prepareDatabaseData(ctx, reconcileCtx)).To(Succeed())
+				Expect(reconcileCtx.BootstrapData.PGPassword).To(Equal(base64.StdEncoding.EncodeToString([]byte("db-password"))))
+
+				By("Mounting the CA bundle into the database bootstrap job")
+				Expect(reconcileCtx.DatabaseData.DBCASecret).To(Equal("external-postgres"))
+				Expect(reconcileCtx.DatabaseData.DBCAKey).To(Equal("ca.crt"))
+				var manifest bytes.Buffer
+				Expect(template.Must(template.New("job").Parse(yamls.DB_BOOTSTRAP_JOB)).Execute(&manifest, reconcileCtx.DatabaseData)).To(Succeed())
+				job := &batchv1.Job{}
+				Expect(yaml.Unmarshal(manifest.Bytes(), job)).To(Succeed())
+				container := job.Spec.Template.Spec.Containers[0]
+				Expect(container.Env).To(ContainElement(corev1.EnvVar{Name: "PGSSLROOTCERT", Value: "/db-ca/ca.crt"}))
+				Expect(container.VolumeMounts).To(ContainElement(corev1.VolumeMount{Name: "db-ca", MountPath: "/db-ca"}))
+				Expect(job.Spec.Template.Spec.Volumes).To(ContainElement(HaveField("Secret.Items", []corev1.KeyToPath{{Key: "ca.crt", Path: "ca.crt"}})))
+
+				Expect(k8sClient.Delete(ctx, dbSecret)).To(Succeed())
+			})
 		})
 
 		Context("Bootstrap Data Functions", func() {
@@ -721,6 +933,144 @@ var _ = Describe("AccountIAM Controller", func() {
 				// Cleanup
 				k8sClient.Delete(ctx, accountIAM)
 			})
+
+			It("should record the failed phase in the status conditions", func() {
+				By("Creating AccountIAM resource")
+				accountIAM := &operatorv1alpha1.AccountIAM{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-phase-conditions",
+						Namespace: AccountIAMNamespace,
+					},
+				}
+				Expect(k8sClient.Create(ctx, accountIAM)).To(Succeed())
+
+				By("Running the phases without the external dependencies")
+				reconcileCtx := &ReconcileContext{Instance: accountIAM}
+				err := reconciler.reconcilePhases(ctx, reconcileCtx)
+				Expect(err).To(HaveOccurred())
+
+				status := accountIAM.Status
+				Expect(status.ObservedGeneration).To(Equal(accountIAM.Generation))
+
+				prereq := meta.FindStatusCondition(status.Conditions, operatorv1alpha1.ConditionTypePrerequisitesReady)
+				Expect(prereq).NotTo(BeNil())
+				Expect(prereq.Status).To(Equal(metav1.ConditionFalse))
+				Expect(prereq.Reason).To(Equal(operatorv1alpha1.ConditionReasonFailed))
+				Expect(prereq.Message).To(Equal(err.Error()))
+
+				ui := meta.FindStatusCondition(status.Conditions, operatorv1alpha1.ConditionTypeUIReady)
+				Expect(ui).NotTo(BeNil())
+				Expect(ui.Status).To(Equal(metav1.ConditionUnknown))
+
+				Expect(meta.IsStatusConditionFalse(status.Conditions, operatorv1alpha1.ConditionTypeReady)).To(BeTrue())
+				Expect(meta.IsStatusConditionTrue(status.Conditions, operatorv1alpha1.ConditionTypeDegraded)).To(BeTrue())
+
+				k8sClient.Delete(ctx, accountIAM)
+			})
+		})
+
+		Context("Finalizer Functions", func() {
+			It("should record the OIDC issuer URL only before the first change", func() {
+				commonService := &unstructured.Unstructured{}
+				Expect(recordIssuer(commonService, "https://previous.example.com", "https://account-iam.example.com", true)).To(BeTrue())
+				Expect(recordIssuer(commonService, "https://account-iam.example.com", "https://new.example.com", true)).To(BeTrue())
+				Expect(recordIssuer(commonService, "", "https://new.example.com", false)).To(BeFalse())
+
+				Expect(commonService.GetAnnotations()).To(HaveKeyWithValue(
+					resources.PreviousOIDCIssuerAnnotation, "https://previous.example.com"))
+				Expect(commonService.GetAnnotations()).To(HaveKeyWithValue(
+					resources.ConfiguredOIDCIssuerAnnotation, "https://new.example.com"))
+			})
+
+			It("should only revert the OIDC issuer URL the operator configured", func() {
+				newCommonService := func(issuerURL string) *unstructured.Unstructured {
+					commonService := &unstructured.Unstructured{Object: map[string]interface{}{
+						"spec": map[string]interface{}{
+							"services": []interface{}{
+								map[string]interface{}{
+									"name": "ibm-im-operator",
+									"spec": map[string]interface{}{
+										"authentication": map[string]interface{}{
+											"config": map[string]interface{}{"oidcIssuerURL": issuerURL},
+										},
+									},
+								},
+							},
+						},
+					}}
+					commonService.SetAnnotations(map[string]string{
+						resources.PreviousOIDCIssuerAnnotation:   "https://previous.example.com",
+						resources.ConfiguredOIDCIssuerAnnotation: "https://account-iam.example.com",
+					})
+					return commonService
+				}
+				issuerURL := func(commonService *unstructured.Unstructured) string {
+					services, _, _ := unstructured.NestedSlice(commonService.Object, "spec", "services")
+					url, _, _ := unstructured.NestedString(services[0].(map[string]interface{}),
+						"spec", "authentication", "config", "oidcIssuerURL")
+					return url
+				}
+
+				By("reverting the URL the operator configured")
+				commonService := newCommonService("https://account-iam.example.com")
+				Expect(revertIssuer(commonService)).To(BeTrue())
+				Expect(issuerURL(commonService)).To(Equal("https://previous.example.com"))
+				Expect(commonService.GetAnnotations()).To(BeEmpty())
+
+				By("keeping the URL changed since the operator configured it")
+				commonService = newCommonService("https://custom.example.com")
+				Expect(revertIssuer(commonService)).To(BeTrue())
+				Expect(issuerURL(commonService)).To(Equal("https://custom.example.com"))
+				Expect(commonService.GetAnnotations()).To(BeEmpty())
+			})
+
+			It("should delete unowned secrets and release the finalizer", func() {
+				By("Creating AccountIAM resource with the cleanup finalizer")
+				accountIAM := &operatorv1alpha1.AccountIAM{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:       "test-finalizer",
+						Namespace:  AccountIAMNamespace,
+						Finalizers: []string{resources.AccountIAMFinalizer},
+					},
+					Spec: operatorv1alpha1.AccountIAMSpec{
+						Uninstall: operatorv1alpha1.UninstallSpec{
+							SecretPolicy: operatorv1alpha1.CleanupPolicyDelete,
+						},
+					},
+				}
+				Expect(k8sClient.Create(ctx, accountIAM)).To(Succeed())
+
+				bootstrapSecret := &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      resources.BootstrapSecret,
+						Namespace: AccountIAMNamespace,
+					},
+					Data: map[string][]byte{resources.PGPasswordKey: []byte("password")},
+				}
+				Expect(k8sClient.Create(ctx, bootstrapSecret)).To(Succeed())
+
+				By("Caching the Account IAM client of the AccountIAM")
+				reconciler.IAMClients = account_iam.NewClientFactory(&retry.Retry{}, account_iam.FactoryOptions{InsecureSkipVerify: true})
+				clientConfig := account_iam.ClientConfig{Namespace: AccountIAMNamespace, APIKey: "api-key"}
+				cachedClient, err := reconciler.IAMClients.ClientFor(string(accountIAM.UID), clientConfig)
+				Expect(err).NotTo(HaveOccurred())
+
+				By("Deleting and reconciling the AccountIAM")
+				Expect(k8sClient.Delete(ctx, accountIAM)).To(Succeed())
+				namespacedName := types.NamespacedName{Name: accountIAM.Name, Namespace: AccountIAMNamespace}
+				_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+				Expect(err).NotTo(HaveOccurred())
+
+				err = k8sClient.Get(ctx, types.NamespacedName{Name: resources.BootstrapSecret, Namespace: AccountIAMNamespace}, &corev1.Secret{})
+				Expect(errors.IsNotFound(err)).To(BeTrue())
+
+				rebuiltClient, err := reconciler.IAMClients.ClientFor(string(accountIAM.UID), clientConfig)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(rebuiltClient).NotTo(BeIdenticalTo(cachedClient))
+
+				err = k8sClient.Get(ctx, namespacedName, &operatorv1alpha1.AccountIAM{})
+				Expect(errors.IsNotFound(err)).To(BeTrue())
+			})
 		})
 	})
 })
@@ -19,30 +19,48 @@ package controller
 import (
 	"context"
 	goerrors "errors"
-	"net/http"
+	"fmt"
 	"os"
+	"strconv"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	logger "github.com/rs/zerolog/log" // TODO: investigate if this is really necessary
 
 	operatorv1alpha1 "github.com/IBM/ibm-user-management-operator/api/v1alpha1"
 	"github.com/IBM/ibm-user-management-operator/client/account_iam"
+	"github.com/IBM/ibm-user-management-operator/internal/iamsync"
+	"github.com/IBM/ibm-user-management-operator/internal/resources"
+	"github.com/IBM/ibm-user-management-operator/internal/retry"
 )
 
 // RoleActionConfigReconciler reconciles a RoleActionConfig object
 type RoleActionConfigReconciler struct {
 	client.Client
-	Scheme    *runtime.Scheme
-	APIClient account_iam.IAMClient
+	Scheme *runtime.Scheme
+	// IAMClients provides the Account IAM client of the AccountIAM each RoleActionConfig is bound to
+	IAMClients account_iam.ClientFactory
+	// SyncTimeout bounds all the calls to Account IAM of a reconcile, zero means no timeout
+	SyncTimeout time.Duration
+	// Safeguard holds back the syncs removing more actions than allowed, the deletion of a RoleActionConfig is not held back
+	Safeguard iamsync.Safeguard
 }
 
 const (
@@ -50,69 +68,119 @@ const (
 )
 
 var (
-	log                     = logf.Log.WithName("controller_roleactionconfig")
-	IAMServiceEndpoint      = ""
-	IAMProductRolesEndpoint = ""
+	log = logf.Log.WithName("controller_roleactionconfig")
+
+	errNoAccountIAM = goerrors.New("no AccountIAM found")
 )
 
-func (r *RoleActionConfigReconciler) PreReq(instance *operatorv1alpha1.RoleActionConfig) error {
-	if _, ok := r.APIClient.(*account_iam.MCSPIAMClient); !ok {
-		err := goerrors.New("the MCSPIAMClient type does not implement IAMClient") // this should never happen unless code was modified incorrectly
-		return err
+// PreReq resolves the AccountIAM the RoleActionConfig is bound to and returns it with the client of its Account IAM service
+func (r *RoleActionConfigReconciler) PreReq(ctx context.Context, instance *operatorv1alpha1.RoleActionConfig) (*operatorv1alpha1.AccountIAM, account_iam.IAMClient, error) {
+	accountIAM, err := r.resolveAccountIAM(ctx, instance)
+	if err != nil {
+		return nil, nil, err
 	}
+	namespace := accountIAM.Namespace
 
-	mcspApiClient := r.APIClient.(*account_iam.MCSPIAMClient)
+	// The API key is read on every reconcile, so a rotated key gets a new client from the factory
+	apiKey, err := r.apiKey(ctx, namespace)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	namespace := ""
+	// The CA bundle is read on every reconcile as well, so the client trusts the rotated CA of cert-manager
+	caBundle, err := r.caBundle(ctx, namespace)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	accountIAMs := &operatorv1alpha1.AccountIAMList{}
-	selector := labels.SelectorFromSet(labels.Set{
-		"operator.ibm.com/opreq-control": "true",
+	apiClient, err := r.IAMClients.ClientFor(string(accountIAM.UID), account_iam.ClientConfig{
+		Namespace: namespace,
+		APIKey:    apiKey,
+		CABundle:  caBundle,
+		APIKeySource: func(ctx context.Context) (string, error) {
+			return r.apiKey(ctx, namespace)
+		},
 	})
-	if err := r.Client.List(context.TODO(), accountIAMs, &client.ListOptions{
-		LabelSelector: selector,
-	}); err != nil {
-		return err
+	if err != nil {
+		return nil, nil, err
+	}
+	return accountIAM, apiClient, nil
+}
+
+// apiKey returns the key of the Account IAM API from the mcsp-im-integration-details secret of a namespace
+func (r *RoleActionConfigReconciler) apiKey(ctx context.Context, namespace string) (string, error) {
+	secret := &corev1.Secret{}
+	if err := r.Client.Get(ctx, types.NamespacedName{
+		Name:      resources.IMAPISecret,
+		Namespace: namespace,
+	}, secret); err != nil {
+		return "", err
 	}
-	if len(accountIAMs.Items) == 0 {
-		return goerrors.New("no account-iam exists yet, waiting")
+	apiKey, ok := secret.Data[resources.MCSPAPIKey]
+	if !ok {
+		return "", fmt.Errorf("secret %s missing %s", resources.IMAPISecret, resources.MCSPAPIKey)
 	}
+	return string(apiKey), nil
+}
 
-	namespace = accountIAMs.Items[0].Namespace
-	if len(accountIAMs.Items) > 1 { // if installing with ODLM, this should not happen
-		// if more than one account-iam svc, then rely on label in RoleActionConfig CR
-		if _, ok := instance.Labels["operator.ibm.com/account-iam-ns"]; !ok {
-			return goerrors.New("found more than one AccountIAM CR and missing 'operator.ibm.com/operator.ibm.com/account-iam-ns' label")
+// caBundle returns the CA certificates the Account IAM service certificate of a namespace is verified against
+func (r *RoleActionConfigReconciler) caBundle(ctx context.Context, namespace string) ([]byte, error) {
+	var bundle []byte
+	for _, name := range []string{resources.AccountIAMCACert, resources.CSCASecret} {
+		secret := &corev1.Secret{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, secret); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		if ca, ok := secret.Data[resources.CAKey]; ok {
+			bundle = append(bundle, ca...)
+			bundle = append(bundle, '\n')
 		}
-		namespace = instance.Labels["operator.ibm.com/operator.ibm.com/account-iam-ns"]
 	}
+	return bundle, nil
+}
 
-	if IAMServiceEndpoint == "" {
-		// fetch namespace of account-iam service
-		IAMServiceEndpoint = "https://account-iam." + namespace + ".svc.cluster.local:9445/api/2.0/accounts/global_account/apikeys/token"
+// resolveAccountIAM returns the AccountIAM the RoleActionConfig is bound to: the one of spec.accountIAMRef,
+// else the one in the namespace of the operator.ibm.com/account-iam-ns label, else the only AccountIAM of the cluster
+func (r *RoleActionConfigReconciler) resolveAccountIAM(ctx context.Context, instance *operatorv1alpha1.RoleActionConfig) (*operatorv1alpha1.AccountIAM, error) {
+	if ref := instance.Spec.AccountIAMRef; ref != nil {
+		namespace := ref.Namespace
+		if namespace == "" {
+			namespace = instance.Namespace
+		}
+		accountIAM := &operatorv1alpha1.AccountIAM{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, accountIAM); err != nil {
+			if errors.IsNotFound(err) {
+				return nil, fmt.Errorf("%w for spec.accountIAMRef %s/%s", errNoAccountIAM, namespace, ref.Name)
+			}
+			return nil, err
+		}
+		return accountIAM, nil
 	}
 
-	if IAMProductRolesEndpoint == "" {
-		// fetch namespace of account-iam service
-		IAMProductRolesEndpoint = "https://account-iam." + namespace + ".svc.cluster.local:9445/api/2.0/products"
-		mcspApiClient.BaseURL = IAMProductRolesEndpoint
+	accountIAMs := &operatorv1alpha1.AccountIAMList{}
+	var listOpts []client.ListOption
+	scope := "the cluster"
+	if namespace, ok := instance.Labels[operatorv1alpha1.AccountIAMNamespaceLabel]; ok {
+		listOpts = append(listOpts, client.InNamespace(namespace))
+		scope = fmt.Sprintf("namespace %s of the %s label", namespace, operatorv1alpha1.AccountIAMNamespaceLabel)
+	} else {
+		listOpts = append(listOpts, client.MatchingLabels{"operator.ibm.com/opreq-control": "true"})
+	}
+	if err := r.Client.List(ctx, accountIAMs, listOpts...); err != nil {
+		return nil, err
 	}
 
-	if mcspApiClient.ApiKey == "" {
-		// fetch from mcsp-im-integration-details secret
-		secret := &corev1.Secret{}
-		if err := r.Client.Get(context.TODO(), types.NamespacedName{
-			Name:      "mcsp-im-integration-details",
-			Namespace: namespace,
-		}, secret); err != nil {
-			return err
-		}
-		if _, ok := secret.Data["API_KEY"]; !ok {
-			return goerrors.New("secret mcsp-im-integration-details missing API_KEY")
-		}
-		mcspApiClient.ApiKey = string(secret.Data["API_KEY"])
+	switch len(accountIAMs.Items) {
+	case 0:
+		return nil, fmt.Errorf("%w in %s", errNoAccountIAM, scope)
+	case 1:
+		return &accountIAMs.Items[0], nil
+	default:
+		return nil, fmt.Errorf("found %d AccountIAMs in %s, set spec.accountIAMRef to select one", len(accountIAMs.Items), scope)
 	}
-	return nil
 }
 
 // +kubebuilder:rbac:groups=operator.ibm.com,namespace="placeholder",resources=roleactionconfigs,verbs=get;list;watch;create;update;patch;delete
@@ -152,217 +220,310 @@ func (r *RoleActionConfigReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		return ctrl.Result{}, err
 	}
 
-	if err := r.PreReq(instance); err != nil {
+	if !instance.DeletionTimestamp.IsZero() {
+		return r.finalize(ctx, instance)
+	}
+
+	// Record the outcome of the sync in the status, whichever way the reconcile ends
+	originalStatus := instance.Status.DeepCopy()
+	status := &operatorv1alpha1.RoleActionConfigStatus{
+		AccountIAM:        originalStatus.AccountIAM,
+		ProductRegistered: originalStatus.ProductRegistered,
+		Roles:             originalStatus.Roles,
+		Actions:           originalStatus.Actions,
+		LastSyncTime:      originalStatus.LastSyncTime,
+		Conditions:        originalStatus.Conditions,
+	}
+	var syncErrs []error
+	defer func() {
+		r.updateStatus(ctx, instance, originalStatus, status, syncErrs)
+	}()
+
+	accountIAM, apiClient, err := r.PreReq(ctx, instance)
+	if err != nil {
+		syncErrs = append(syncErrs, err)
 		return ctrl.Result{}, err
 	}
+	status.AccountIAM = &operatorv1alpha1.AccountIAMReference{Name: accountIAM.Name, Namespace: accountIAM.Namespace}
+
+	// Bound the calls to Account IAM, the status is still recorded with ctx once the deadline is exceeded
+	syncCtx, cancel := r.syncContext(ctx)
+	defer cancel()
+
+	// Account IAM is reachable from here on, make sure the product is deregistered on deletion
+	if !controllerutil.ContainsFinalizer(instance, resources.RoleActionConfigFinalizer) {
+		controllerutil.AddFinalizer(instance, resources.RoleActionConfigFinalizer)
+		if err := r.Client.Update(ctx, instance); err != nil {
+			syncErrs = append(syncErrs, err)
+			return ctrl.Result{}, err
+		}
+	}
 
-	// Fetch serviceID, v2CustomRoles, product level actions fields.
 	serviceID := instance.Spec.ServiceID
-	v2CustomRolesSection := instance.Spec.IAM.V2CustomRoles
-	crActionsProductLevel := instance.Spec.IAM.Actions
 
 	// POST request to account IAM /api/2.0/accounts/global_account/apikeys/token.
-	IAMServiceEndpoint := IAMServiceEndpoint
-
-	_, err = r.APIClient.GetToken(IAMServiceEndpoint)
+	_, err = apiClient.GetToken(syncCtx, r.IAMClients.TokenURL(accountIAM.Namespace))
 	if err != nil {
 		log.Error(err, "failed to get token")
-		return ctrl.Result{}, nil
+		syncErrs = append(syncErrs, fmt.Errorf("failed to get token: %w", err))
+		return syncResult(syncErrs)
 	}
 
-	var UIDs map[string]string
-	var getUIDstatusCode int
-
-	// GET request to account IAM /api/2.0/products/{productId} to get product details and see if it exists in account IAM.
-	// Note: account IAM "productId" is product reg yaml "serviceId"
+	// Diff the product in Account IAM with the spec and apply the difference
+	state, err := iamsync.Observe(syncCtx, apiClient, serviceID)
+	if err != nil {
+		log.Error(err, "failed to observe product in Account IAM", "serviceID", serviceID)
+		syncErrs = append(syncErrs, err)
+		return syncResult(syncErrs)
+	}
+	ops := iamsync.Plan(instance.Spec, state)
+	logger.Info().Msgf("Planned %d operations to sync product %s with Account IAM", len(ops), serviceID)
 
-	_, getProductDetailsStatusCode, err := r.APIClient.GetProductDetails(serviceID)
+	// Hold back a pruning removing more actions than allowed until it is acknowledged
+	verdict, err := r.Safeguard.Check(state, ops)
 	if err != nil {
-		log.Error(err, "Failed to GET product details from Account IAM.")
+		syncErrs = append(syncErrs, retry.NewPermanentError(err))
+		return syncResult(syncErrs)
+	}
+	blocked := len(verdict.Blocked) > 0 && !pruningAcknowledged(instance)
+	if blocked {
+		logger.Info().Msgf("Holding back %d pruning operations of product %s: %s", len(verdict.Blocked), serviceID, verdict.Describe())
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:   operatorv1alpha1.ConditionTypeBlocked,
+			Status: metav1.ConditionTrue,
+			Reason: operatorv1alpha1.ConditionReasonPruneBlocked,
+			Message: fmt.Sprintf("Pruning is blocked, %s. Set the %s annotation to %d to let it proceed",
+				verdict.Describe(), operatorv1alpha1.AcknowledgePruningAnnotation, instance.Generation),
+			ObservedGeneration: instance.Generation,
+		})
 	} else {
-		logger.Info().Msgf("Successfully made request to GET product details API call. Status Code: %d", getProductDetailsStatusCode)
+		meta.RemoveStatusCondition(&status.Conditions, operatorv1alpha1.ConditionTypeBlocked)
 	}
 
-	// Check if the product has been registered on Account IAM.
-	// If response is 404, product is unknown to Account IAM and we need to register the product.
-	if getProductDetailsStatusCode == http.StatusNotFound {
-		logger.Info().Msgf("Product %s not found in Account IAM (Status Code: %d). Proceed to make POST request to register the product.", req.Name, getProductDetailsStatusCode)
-
-		postNewProduct, postNewProductStatusCode, err := r.APIClient.PostNewProduct(serviceID)
-
-		if err != nil {
-			log.Error(err, "Failed to POST and register new product to Account IAM.")
-		} else {
-			logger.Info().Msgf("Successfully made POST request to register product %s to Account IAM. Status: %s, Status Code: %d", serviceID, string(postNewProduct), postNewProductStatusCode)
+	planOnly := instance.Spec.SyncMode == operatorv1alpha1.SyncModePlan
+	var outcomes map[string]string
+	if planOnly {
+		// Report the requests of the plan instead of sending them, the product is reported as observed.
+		// The pruning held back is reported too, since previewing it is what the plan is for.
+		var heldBack []iamsync.Operation
+		if blocked {
+			heldBack = verdict.Blocked
 		}
+		status.PlannedOperations = plannedOperations(serviceID, state, ops, heldBack)
+	} else {
+		if blocked {
+			ops = verdict.Allowed
+		}
+		executor := &iamsync.Executor{Client: apiClient, Instance: instance}
+		var execErrs []error
+		state, outcomes, execErrs = executor.Execute(syncCtx, state, ops)
+		syncErrs = append(syncErrs, execErrs...)
 	}
 
-	// Check if V2CustomRoles field exists before executing API calls
-	if v2CustomRolesSection != nil {
-
-		// Get all custom roles by GET request to account IAM /api/2.0/products/{scopeId}/roles API.
-		UIDs, getUIDstatusCode, err = r.APIClient.GetUID(instance)
-		if err != nil {
-			log.Error(err, "failed to do GET request to product custom roles API.")
-			return ctrl.Result{}, nil
+	// Report the product, its actions and the custom roles of the spec as they are in Account IAM
+	status.ProductRegistered = state.ProductRegistered
+	status.Actions = sets.List(state.Actions)
+	roles := make([]operatorv1alpha1.RoleStatus, 0, len(instance.Spec.IAM.V2CustomRoles))
+	for _, v2CustomRole := range instance.Spec.IAM.V2CustomRoles {
+		roleStatus := operatorv1alpha1.RoleStatus{Name: v2CustomRole.Name}
+		if role, ok := state.Roles[v2CustomRole.Name]; ok {
+			roleStatus.UID = role.UID
+			roleStatus.Actions = sets.List(role.Actions)
 		}
-		// Log the status of the GET request
-		logger.Info().Msgf("Successfully made request to GET product custom roles API. Status Code: %d", getUIDstatusCode)
-
-		// Check if the product has been registered on Account IAM
-		if getUIDstatusCode == http.StatusNotFound {
-			logger.Info().Msgf("Product %s not found in Account IAM (Status Code: %d). No further actions needed.", req.Name, getUIDstatusCode)
+		if !planOnly {
+			roleStatus.Outcome = operatorv1alpha1.RoleOutcomeUnchanged
+			if outcome, ok := outcomes[v2CustomRole.Name]; ok {
+				roleStatus.Outcome = outcome
+			}
 		}
+		roles = append(roles, roleStatus)
+	}
+	status.Roles = roles
 
-		for _, v2CustomRole := range instance.Spec.IAM.V2CustomRoles {
-			// When account IAM returns a status code of 404 (not found), product is not registered with AccountIAM therefore we don't need to execute these requests.
-			if getUIDstatusCode != http.StatusNotFound {
-				// Create custom role by POST request to account IAM /api/2.0/products API.
-				customRoles, postStatusCode, err := r.APIClient.PostCustomRoles(v2CustomRole, serviceID)
+	return syncResult(syncErrs)
+}
 
-				if err != nil {
-					log.Error(err, "failed to do POST request to product custom roles API.")
-				}
+// pruningAcknowledged reports whether the pruning of the current generation of the RoleActionConfig is acknowledged
+func pruningAcknowledged(instance *operatorv1alpha1.RoleActionConfig) bool {
+	return instance.Annotations[operatorv1alpha1.AcknowledgePruningAnnotation] == strconv.FormatInt(instance.Generation, 10)
+}
 
-				logger.Info().Msgf("Successfully made request to POST product custom roles API. Status Message: %s. Status Code: %d", string(customRoles), postStatusCode)
+// plannedOperations reports the operations planned for the product of serviceID in state, marking the ones in blocked
+func plannedOperations(serviceID string, state *iamsync.State, ops []iamsync.Operation, blocked []iamsync.Operation) []operatorv1alpha1.PlannedOperation {
+	heldBack := sets.New(blocked...)
+	var planned []operatorv1alpha1.PlannedOperation
+	for _, op := range ops {
+		planned = append(planned, operatorv1alpha1.PlannedOperation{
+			Type:    string(op.Kind),
+			Request: op.Request(serviceID, state),
+			Role:    op.Role,
+			Action:  op.Action,
+			Blocked: heldBack.Has(op),
+		})
+	}
+	return planned
+}
 
-				// Update custom roles by PATCH request to account IAM /api/2.0/products API.
-				updateCustomRoles, updateStatusCode, err := r.APIClient.UpdateCustomRoles(v2CustomRole, serviceID, UIDs[v2CustomRole.Name])
+// syncResult turns the errors of a sync into the result of the reconcile.
+// Transient errors are returned so the request is requeued with backoff, while
+// a sync that only failed with permanent errors is not retried until the spec changes.
+func syncResult(syncErrs []error) (ctrl.Result, error) {
+	if len(syncErrs) == 0 {
+		return ctrl.Result{}, nil
+	}
 
-				if err != nil {
-					log.Error(err, "failed to do PATCH request to product custom roles API.")
-				}
+	aggregate := utilerrors.NewAggregate(syncErrs)
+	for _, err := range syncErrs {
+		if !retry.IsPermanentError(err) {
+			return ctrl.Result{}, aggregate
+		}
+	}
+	return ctrl.Result{}, reconcile.TerminalError(aggregate)
+}
 
-				logger.Info().Msgf("Successfully made request to PATCH product custom roles API. Status Message: %s. Status Code: %d", string(updateCustomRoles), updateStatusCode)
-			}
+// finalize applies the deletion policy of a RoleActionConfig being deleted and releases its finalizer
+func (r *RoleActionConfigReconciler) finalize(ctx context.Context, instance *operatorv1alpha1.RoleActionConfig) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(instance, resources.RoleActionConfigFinalizer) {
+		return ctrl.Result{}, nil
+	}
 
-			// Delete custom roles by DELETE request to account IAM /api/2.0/products API.
-			if len(UIDs) > 0 && getUIDstatusCode != http.StatusNotFound {
-				for name, UID := range UIDs {
-					var found bool
-					for _, v2CustomRole := range instance.Spec.IAM.V2CustomRoles {
-						if name == v2CustomRole.Name {
-							found = true
-						}
-					}
-
-					if !found {
-						deleteCustomRoles, statusCode, err := r.APIClient.DeleteCustomRoles(instance, UID)
-
-						if err != nil {
-							log.Error(err, "failed to do DELETE request to product custom roles API.")
-						}
-
-						logger.Info().Msgf("Successfully made request to DELETE product custom roles API. Status Message: %s. Status Code: %d", string(deleteCustomRoles), statusCode)
-					}
-				}
-			}
+	if instance.Spec.DeletionPolicy == operatorv1alpha1.DeletionPolicyOrphan {
+		logger.Info().Msgf("Deletion policy of %s is Orphan, leaving product %s in Account IAM", instance.Name, instance.Spec.ServiceID)
+	} else if instance.Spec.SyncMode == operatorv1alpha1.SyncModePlan {
+		logger.Info().Msgf("Sync mode of %s is Plan, leaving product %s in Account IAM", instance.Name, instance.Spec.ServiceID)
+	} else if err := r.deregister(ctx, instance); err != nil {
+		log.Error(err, "failed to deregister product from Account IAM", "serviceID", instance.Spec.ServiceID)
+		meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
+			Type:               operatorv1alpha1.ConditionTypeReady,
+			Status:             metav1.ConditionFalse,
+			Reason:             operatorv1alpha1.ConditionReasonCleanupFailed,
+			Message:            err.Error(),
+			ObservedGeneration: instance.Generation,
+		})
+		if statusErr := r.Client.Status().Update(ctx, instance); statusErr != nil {
+			log.Error(statusErr, "failed to update RoleActionConfig status")
 		}
+		return ctrl.Result{}, err
 	}
-	// Check if actions at product level exists before executing API calls
-	if crActionsProductLevel != nil {
-		// GET all product level actions from account IAM /api/2.0/products/{scopeId}/actions API.
-		getActionsProductLevel, getStatusCode, err := r.APIClient.GetActionsProductLevel(serviceID)
-		if err != nil {
-			log.Error(err, "failed to do GET list actions API.")
-		}
-		// Log the status of the GET request
-		logger.Info().Msgf("Successfully made request to GET product list actions API. Status Code: %d", getStatusCode)
 
-		// Check if the product has been registered on Account IAM
-		if getStatusCode == http.StatusNotFound {
-			logger.Info().Msgf("Product %s not found in Account IAM (Status Code: %d). No further actions needed.", req.Name, getStatusCode)
-		}
+	controllerutil.RemoveFinalizer(instance, resources.RoleActionConfigFinalizer)
+	if err := r.Client.Update(ctx, instance); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
 
-		if getStatusCode != http.StatusNotFound {
-			// Format GET response with list of actions
-			actionsProductLevel := sets.New[string]()
-			for _, singleItem := range getActionsProductLevel {
-				actionsProductLevel.Insert(singleItem["name"])
-			}
+// deregister removes the custom roles, role level actions and product level actions of a product from Account IAM
+func (r *RoleActionConfigReconciler) deregister(ctx context.Context, instance *operatorv1alpha1.RoleActionConfig) error {
+	syncCtx, cancel := r.syncContext(ctx)
+	defer cancel()
 
-			// Format product registration list of actions
-			productRegistrationActions := sets.New[string]()
-			for _, action := range instance.Spec.IAM.Actions {
-				productRegistrationActions.Insert(action)
-			}
-			// Find the differences between IAM list of actions and product registration list of actions
-			diff := actionsProductLevel.Difference(productRegistrationActions)
-			diff2 := productRegistrationActions.Difference(actionsProductLevel)
-			for actionProductLevel := range diff2 {
-				// POST request to account IAM /api/2.0/products/{scopeId}/actions API.
-
-				postActionsProductLevel, statusCode, err := r.APIClient.PostActionsProductLevel(actionProductLevel, serviceID)
-				if err != nil {
-					log.Error(err, "failed to do POST request to product level actions API.")
-				}
-
-				logger.Info().Msgf("Successfully made request to POST product level actions API. Status Message: %s. Status Code: %d", string(postActionsProductLevel), statusCode)
-			}
-			// DELETE request to account IAM /api/2.0/products/{scopeId}/actions/{action} API with actions removed from product registration.
-			for actionName := range diff {
-				deleteActionsProductLevel, statusCode, err := r.APIClient.DeleteActionsProductLevel(serviceID, actionName)
-				if err != nil {
-					log.Error(err, "failed to do DELETE request to list actions API.")
-				}
-				logger.Info().Msgf("Successfully made request to DELETE list actions API. Status Message: %s. Status Code: %d", string(deleteActionsProductLevel), statusCode)
-			}
+	accountIAM, apiClient, err := r.PreReq(ctx, instance)
+	if err != nil {
+		if goerrors.Is(err, errNoAccountIAM) {
+			// Account IAM is gone together with everything registered in it
+			logger.Info().Msgf("No AccountIAM found, nothing to deregister for %s", instance.Name)
+			return nil
 		}
-		// Check if actions at custom role level exists before executing API calls
-		for _, v2CustomRole := range instance.Spec.IAM.V2CustomRoles {
-			if v2CustomRole.Actions != nil && getUIDstatusCode != http.StatusNotFound {
-				// GET all role level actions from account IAM /api/2.0/products/{scopeId}/roles/{roleUid}/actions API.
-				getActionsRoleLevel, statusCode, err := r.APIClient.GetActionsRoleLevel(serviceID, UIDs[v2CustomRole.Name])
-
-				if err != nil {
-					log.Error(err, "failed to do GET list actions at role level API.")
-				}
-				logger.Info().Msgf("Successfully made request to GET list actions at role level API. Status Code: %d", statusCode)
-
-				// Format GET response with list of role actions
-				actionsRoleLevel := sets.New[string]()
-				for _, singleItem := range getActionsRoleLevel {
-					actionsRoleLevel.Insert(singleItem["name"])
-				}
-
-				// Format product registration list of role actions
-				productRegistrationActions := sets.New[string]()
-				for _, action := range v2CustomRole.Actions {
-					productRegistrationActions.Insert(serviceID + "." + action)
-				}
-
-				// Find the differences between product registration list of role actions and IAM list of role actions
-				diff := productRegistrationActions.Difference(actionsRoleLevel)
-				for actionRoleLevel := range diff {
-					// POST request to account IAM  /api/2.0/products/{scopeId}/roles/{roleUid}/actions API.
-					postActionsRoleLevel, statusCode, err := r.APIClient.PostActionsRoleLevel(actionRoleLevel, UIDs[v2CustomRole.Name], serviceID)
-					if err != nil {
-						log.Error(err, "failed to do POST request to role level actions API.")
-					}
-					logger.Info().Msgf("Successfully made request to POST role level actions API. Status Message: %s. Status Code: %d", string(postActionsRoleLevel), statusCode)
-				}
-				// Find the differences between IAM list of role actions and product registration list of role actions
-				diff = actionsRoleLevel.Difference(productRegistrationActions)
-
-				for actionRoleLevel := range diff {
-					// DELETE request to account IAM /api/2.0/products/{scopeId}/roles/{roleUid}/actions/{action} API.
-					deleteActionsProductLevel, statusCode, err := r.APIClient.DeleteActionsRoleLevel(serviceID, UIDs[v2CustomRole.Name], actionRoleLevel)
-					if err != nil {
-						log.Error(err, "failed to do DELETE request to role level actions API.")
-					}
-					logger.Info().Msgf("Successfully made request to DELETE role level actions API. Status Message: %s. Status Code: %d", string(deleteActionsProductLevel), statusCode)
-				}
+		return err
+	}
+	if accountIAM.DeletionTimestamp != nil {
+		// The client would otherwise be cached again after the AccountIAM finalizer evicted it
+		defer r.IAMClients.Evict(string(accountIAM.UID))
+	}
 
-			}
-		}
+	if _, err := apiClient.GetToken(syncCtx, r.IAMClients.TokenURL(accountIAM.Namespace)); err != nil {
+		return fmt.Errorf("failed to get token: %w", err)
 	}
 
-	return ctrl.Result{}, nil
+	serviceID := instance.Spec.ServiceID
+	state, err := iamsync.Observe(syncCtx, apiClient, serviceID)
+	if err != nil {
+		return err
+	}
+	if !state.ProductRegistered {
+		logger.Info().Msgf("Product %s not found in Account IAM, nothing to deregister", serviceID)
+		return nil
+	}
+
+	// Plan towards a spec with empty custom roles and actions, the product itself stays registered
+	ops := iamsync.Plan(operatorv1alpha1.RoleActionConfigSpec{
+		ServiceID: serviceID,
+		IAM:       operatorv1alpha1.IAM{Actions: []string{}, V2CustomRoles: []operatorv1alpha1.V2CustomRoles{}},
+	}, state)
+	executor := &iamsync.Executor{Client: apiClient, Instance: instance}
+	if _, _, cleanupErrs := executor.Execute(syncCtx, state, ops); len(cleanupErrs) > 0 {
+		return utilerrors.NewAggregate(cleanupErrs)
+	}
+
+	logger.Info().Msgf("Deregistered custom roles and actions of product %s from Account IAM", serviceID)
+	return nil
+}
+
+// syncContext returns the context bounding the calls of a sync to Account IAM
+func (r *RoleActionConfigReconciler) syncContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.SyncTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, r.SyncTimeout)
+}
+
+// updateStatus records the outcome of a sync in the RoleActionConfig status
+func (r *RoleActionConfigReconciler) updateStatus(ctx context.Context, instance *operatorv1alpha1.RoleActionConfig,
+	originalStatus, status *operatorv1alpha1.RoleActionConfigStatus, syncErrs []error) {
+	status.ObservedGeneration = instance.Generation
+
+	condition := metav1.Condition{
+		Type:               operatorv1alpha1.ConditionTypeReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             operatorv1alpha1.ConditionReasonSynced,
+		Message:            "Product is in sync with Account IAM",
+		ObservedGeneration: instance.Generation,
+	}
+	switch {
+	case len(syncErrs) > 0:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = operatorv1alpha1.ConditionReasonSyncFailed
+		condition.Message = utilerrors.NewAggregate(syncErrs).Error()
+	case len(status.PlannedOperations) > 0:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = operatorv1alpha1.ConditionReasonPlanned
+		condition.Message = fmt.Sprintf("%d operations are planned, set spec.syncMode to %s to send them to Account IAM",
+			len(status.PlannedOperations), operatorv1alpha1.SyncModeApply)
+	case meta.IsStatusConditionTrue(status.Conditions, operatorv1alpha1.ConditionTypeBlocked):
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = operatorv1alpha1.ConditionReasonPruneBlocked
+		condition.Message = meta.FindStatusCondition(status.Conditions, operatorv1alpha1.ConditionTypeBlocked).Message
+	default:
+		now := metav1.Now()
+		status.LastSyncTime = &now
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+
+	if equality.Semantic.DeepEqual(originalStatus, status) {
+		return
+	}
+
+	instance.Status = *status
+	if err := r.Client.Status().Update(ctx, instance); err != nil {
+		log.Error(err, "failed to update RoleActionConfig status")
+	}
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *RoleActionConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// Status updates must not trigger another sync with Account IAM, unlike the acknowledgement of a blocked pruning
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&operatorv1alpha1.RoleActionConfig{}).
+		For(&operatorv1alpha1.RoleActionConfig{}, builder.WithPredicates(
+			predicate.Or[client.Object](predicate.GenerationChangedPredicate{}, predicate.LabelChangedPredicate{},
+				pruningAcknowledgedPredicate))).
 		Complete(r)
 }
+
+// pruningAcknowledgedPredicate passes the updates changing the acknowledgement of a blocked pruning
+var pruningAcknowledgedPredicate = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		key := operatorv1alpha1.AcknowledgePruningAnnotation
+		return e.ObjectOld.GetAnnotations()[key] != e.ObjectNew.GetAnnotations()[key]
+	},
+}
@@ -18,19 +18,154 @@ package controller
 
 import (
 	"context"
+	goerrors "errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	operatorv1alpha1 "github.com/IBM/ibm-user-management-operator/api/v1alpha1"
+	"github.com/IBM/ibm-user-management-operator/client/account_iam"
+	"github.com/IBM/ibm-user-management-operator/client/account_iam/fake"
+	"github.com/IBM/ibm-user-management-operator/internal/iamsync"
+	"github.com/IBM/ibm-user-management-operator/internal/resources"
+	"github.com/IBM/ibm-user-management-operator/internal/retry"
 )
 
+// newSyncFixture serves a fake Account IAM accepting the API key of the AccountIAM named name, which it
+// creates with its API key secret, and returns a reconciler syncing RoleActionConfigs with it
+func newSyncFixture(name string, opts ...fake.Option) (*fake.Server, *RoleActionConfigReconciler) {
+	ctx := context.Background()
+
+	fakeIAM := fake.NewServer(append([]fake.Option{fake.WithAPIKey("test-api-key")}, opts...)...)
+	DeferCleanup(fakeIAM.Close)
+
+	accountIAM := &operatorv1alpha1.AccountIAM{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+	}
+	Expect(k8sClient.Create(ctx, accountIAM)).To(Succeed())
+	DeferCleanup(k8sClient.Delete, ctx, accountIAM)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: resources.IMAPISecret, Namespace: "default"},
+		Data:       map[string][]byte{resources.MCSPAPIKey: []byte("test-api-key")},
+	}
+	Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+	DeferCleanup(k8sClient.Delete, ctx, secret)
+
+	return fakeIAM, &RoleActionConfigReconciler{
+		Client: k8sClient,
+		Scheme: k8sClient.Scheme(),
+		IAMClients: account_iam.NewClientFactory(
+			&retry.Retry{BackoffInterval: time.Millisecond, BackoffMultiplier: 1, BackoffMaxRetries: 2},
+			account_iam.FactoryOptions{
+				InsecureSkipVerify: true,
+				ServiceURL:         func(string) string { return fakeIAM.URL },
+			}),
+	}
+}
+
 var _ = Describe("RoleActionConfig Controller", func() {
+	Context("When turning sync errors into a result", func() {
+		It("should not requeue without errors", func() {
+			result, err := syncResult(nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(reconcile.Result{}))
+		})
+
+		It("should requeue when any error is transient", func() {
+			_, err := syncResult([]error{
+				retry.NewPermanentError(goerrors.New("GET returned client error: 404")),
+				fmt.Errorf("failed to create action: %w", goerrors.New("GET returned server error: 503")),
+			})
+			Expect(err).To(HaveOccurred())
+			Expect(goerrors.Is(err, reconcile.TerminalError(nil))).To(BeFalse())
+		})
+
+		It("should stop requeueing when every error is permanent", func() {
+			_, err := syncResult([]error{
+				fmt.Errorf("failed to get custom roles: %w", retry.NewPermanentError(goerrors.New("GET returned client error: 403"))),
+			})
+			Expect(err).To(HaveOccurred())
+			Expect(goerrors.Is(err, reconcile.TerminalError(nil))).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("403"))
+		})
+	})
+
+	Context("When resolving the AccountIAM of a resource", func() {
+		ctx := context.Background()
+		reconciler := &RoleActionConfigReconciler{}
+
+		BeforeEach(func() {
+			reconciler.Client = k8sClient
+			reconciler.Scheme = k8sClient.Scheme()
+		})
+
+		newAccountIAM := func(name string) *operatorv1alpha1.AccountIAM {
+			accountIAM := &operatorv1alpha1.AccountIAM{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			}
+			Expect(k8sClient.Create(ctx, accountIAM)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, accountIAM)
+			return accountIAM
+		}
+
+		It("should bind the AccountIAM of spec.accountIAMRef", func() {
+			newAccountIAM("referenced-accountiam")
+			newAccountIAM("other-accountiam")
+
+			instance := &operatorv1alpha1.RoleActionConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "referencing", Namespace: "default"},
+				Spec: operatorv1alpha1.RoleActionConfigSpec{
+					AccountIAMRef: &operatorv1alpha1.AccountIAMReference{Name: "referenced-accountiam"},
+				},
+			}
+			accountIAM, err := reconciler.resolveAccountIAM(ctx, instance)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(accountIAM.Name).To(Equal("referenced-accountiam"))
+			Expect(accountIAM.Namespace).To(Equal("default"))
+		})
+
+		It("should fail when the reference is missing", func() {
+			instance := &operatorv1alpha1.RoleActionConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "referencing", Namespace: "default"},
+				Spec: operatorv1alpha1.RoleActionConfigSpec{
+					AccountIAMRef: &operatorv1alpha1.AccountIAMReference{Name: "missing", Namespace: "other"},
+				},
+			}
+			_, err := reconciler.resolveAccountIAM(ctx, instance)
+			Expect(goerrors.Is(err, errNoAccountIAM)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("other/missing"))
+		})
+
+		It("should fail when the label is ambiguous", func() {
+			newAccountIAM("first-accountiam")
+			newAccountIAM("second-accountiam")
+
+			instance := &operatorv1alpha1.RoleActionConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "labelled",
+					Namespace: "default",
+					Labels:    map[string]string{operatorv1alpha1.AccountIAMNamespaceLabel: "default"},
+				},
+			}
+			_, err := reconciler.resolveAccountIAM(ctx, instance)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("set spec.accountIAMRef"))
+		})
+	})
+
 	Context("When reconciling a resource", func() {
 		const resourceName = "test-resource"
 
@@ -80,5 +215,289 @@ var _ = Describe("RoleActionConfig Controller", func() {
 			// TODO(user): Add more specific assertions depending on your controller's reconciliation logic.
 			// Example: If you expect a certain status condition after reconciliation, verify it here.
 		})
+
+		It("should report a failed sync in the status", func() {
+			By("Reconciling without any AccountIAM instance")
+			controllerReconciler := &RoleActionConfigReconciler{
+				Client:     k8sClient,
+				Scheme:     k8sClient.Scheme(),
+				IAMClients: account_iam.NewClientFactory(&retry.Retry{}, account_iam.FactoryOptions{}),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).To(HaveOccurred())
+
+			By("Checking the Ready condition carries the error")
+			resource := &operatorv1alpha1.RoleActionConfig{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.ObservedGeneration).To(Equal(resource.Generation))
+			Expect(resource.Status.LastSyncTime).To(BeNil())
+
+			ready := meta.FindStatusCondition(resource.Status.Conditions, operatorv1alpha1.ConditionTypeReady)
+			Expect(ready).NotTo(BeNil())
+			Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+			Expect(ready.Reason).To(Equal(operatorv1alpha1.ConditionReasonSyncFailed))
+			Expect(ready.Message).To(ContainSubstring(err.Error()))
+		})
+	})
+
+	Context("When syncing a resource with Account IAM", func() {
+		ctx := context.Background()
+
+		It("should register and deregister the product actions and custom roles", func() {
+			fakeIAM, controllerReconciler := newSyncFixture("synced-accountiam", fake.WithPageSize(2))
+
+			typeNamespacedName := types.NamespacedName{Name: "synced-resource", Namespace: "default"}
+			resource := &operatorv1alpha1.RoleActionConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: typeNamespacedName.Name, Namespace: typeNamespacedName.Namespace},
+				Spec: operatorv1alpha1.RoleActionConfigSpec{
+					ServiceID:     "synced-product",
+					AccountIAMRef: &operatorv1alpha1.AccountIAMReference{Name: "synced-accountiam"},
+					IAM: operatorv1alpha1.IAM{
+						Actions: []string{"read", "write", "delete"},
+						V2CustomRoles: []operatorv1alpha1.V2CustomRoles{
+							{Name: "viewer", Description: "Viewer", Actions: []string{"read"}},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			By("Reconciling the resource through a transient failure")
+			fakeIAM.InjectFailures(http.StatusServiceUnavailable, 1)
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			product, ok := fakeIAM.Product("synced-product")
+			Expect(ok).To(BeTrue())
+			Expect(product.Actions).To(ConsistOf("read", "write", "delete"))
+			Expect(product.Roles).To(HaveLen(1))
+			Expect(product.Roles[0].Actions).To(ConsistOf("synced-product.read"))
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.ProductRegistered).To(BeTrue())
+			Expect(resource.Status.Actions).To(ConsistOf("read", "write", "delete"))
+			Expect(resource.Status.Roles).To(ConsistOf(operatorv1alpha1.RoleStatus{
+				Name:    "viewer",
+				UID:     product.Roles[0].UID,
+				Actions: []string{"synced-product.read"},
+				Outcome: operatorv1alpha1.RoleOutcomeCreated,
+			}))
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, operatorv1alpha1.ConditionTypeReady)).To(BeTrue())
+
+			By("Reconciling the resource in sync")
+			rolesPath := account_iam.ProductsPath + "/synced-product/roles"
+			rolePath := rolesPath + "/" + product.Roles[0].UID
+			roleCreations := fakeIAM.Requests(http.MethodPost, rolesPath)
+			roleActionAdditions := fakeIAM.Requests(http.MethodPost, rolePath+"/actions")
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeIAM.Requests(http.MethodPost, rolesPath)).To(Equal(roleCreations))
+			Expect(fakeIAM.Requests(http.MethodPost, rolePath+"/actions")).To(Equal(roleActionAdditions))
+			Expect(fakeIAM.Requests(http.MethodPatch, rolePath)).To(BeZero())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.Roles[0].Outcome).To(Equal(operatorv1alpha1.RoleOutcomeUnchanged))
+
+			By("Deleting the resource with expired tokens")
+			fakeIAM.ExpireTokens()
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			product, _ = fakeIAM.Product("synced-product")
+			Expect(product.Actions).To(BeEmpty())
+			Expect(product.Roles).To(BeEmpty())
+			err = k8sClient.Get(ctx, typeNamespacedName, &operatorv1alpha1.RoleActionConfig{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+
+	Context("When planning the sync of a resource", func() {
+		ctx := context.Background()
+
+		It("should report the planned operations without changing Account IAM", func() {
+			fakeIAM, controllerReconciler := newSyncFixture("planned-accountiam")
+			fakeIAM.RegisterProduct("planned-product", fake.Product{
+				Actions: []string{"read", "stale"},
+				Roles: []fake.Role{
+					{UID: "viewer-uid", Name: "viewer", Description: "Viewer", Actions: []string{"planned-product.stale"}},
+				},
+			})
+
+			typeNamespacedName := types.NamespacedName{Name: "planned-resource", Namespace: "default"}
+			resource := &operatorv1alpha1.RoleActionConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: typeNamespacedName.Name, Namespace: typeNamespacedName.Namespace},
+				Spec: operatorv1alpha1.RoleActionConfigSpec{
+					ServiceID:     "planned-product",
+					AccountIAMRef: &operatorv1alpha1.AccountIAMReference{Name: "planned-accountiam"},
+					SyncMode:      operatorv1alpha1.SyncModePlan,
+					IAM: operatorv1alpha1.IAM{
+						Actions: []string{"read", "write"},
+						V2CustomRoles: []operatorv1alpha1.V2CustomRoles{
+							{Name: "viewer", Description: "Viewer", Actions: []string{"read"}},
+							{Name: "editor", Description: "Editor", Actions: []string{"write"}},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			noDeletions := intstr.FromInt32(0)
+			controllerReconciler.Safeguard = iamsync.Safeguard{MaxDeletions: &noDeletions}
+
+			By("Reconciling the resource in Plan mode")
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			const productPath = account_iam.ProductsPath + "/planned-product"
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.PlannedOperations).To(Equal([]operatorv1alpha1.PlannedOperation{
+				{Type: "AddProductAction", Request: "POST " + productPath + "/actions", Action: "write"},
+				{Type: "CreateRole", Request: "POST " + productPath + "/roles", Role: "editor"},
+				{Type: "AddRoleAction", Request: "POST " + productPath + "/roles/{editor}/actions",
+					Role: "editor", Action: "planned-product.write"},
+				{Type: "AddRoleAction", Request: "POST " + productPath + "/roles/viewer-uid/actions",
+					Role: "viewer", Action: "planned-product.read"},
+				{Type: "RemoveRoleAction", Request: "DELETE " + productPath + "/roles/viewer-uid/actions/planned-product.stale",
+					Role: "viewer", Action: "planned-product.stale", Blocked: true},
+				{Type: "RemoveProductAction", Request: "DELETE " + productPath + "/actions/stale", Action: "stale", Blocked: true},
+			}))
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, operatorv1alpha1.ConditionTypeBlocked)).To(BeTrue())
+			Expect(resource.Status.Actions).To(ConsistOf("read", "stale"))
+			Expect(resource.Status.LastSyncTime).To(BeNil())
+			ready := meta.FindStatusCondition(resource.Status.Conditions, operatorv1alpha1.ConditionTypeReady)
+			Expect(ready).NotTo(BeNil())
+			Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+			Expect(ready.Reason).To(Equal(operatorv1alpha1.ConditionReasonPlanned))
+
+			By("Checking Account IAM is unchanged")
+			Expect(fakeIAM.Requests(http.MethodPost, productPath+"/actions")).To(BeZero())
+			Expect(fakeIAM.Requests(http.MethodPost, productPath+"/roles")).To(BeZero())
+			Expect(fakeIAM.Requests(http.MethodDelete, productPath+"/actions/stale")).To(BeZero())
+			product, _ := fakeIAM.Product("planned-product")
+			Expect(product.Actions).To(ConsistOf("read", "stale"))
+			Expect(product.Roles).To(HaveLen(1))
+
+			By("Deleting the resource in Plan mode")
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			product, _ = fakeIAM.Product("planned-product")
+			Expect(product.Actions).To(ConsistOf("read", "stale"))
+			Expect(product.Roles).To(HaveLen(1))
+			err = k8sClient.Get(ctx, typeNamespacedName, &operatorv1alpha1.RoleActionConfig{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+
+	Context("When pruning most of the actions of a resource", func() {
+		ctx := context.Background()
+
+		It("should hold back the pruning until it is acknowledged", func() {
+			fakeIAM, controllerReconciler := newSyncFixture("pruned-accountiam")
+			fakeIAM.RegisterProduct("pruned-product", fake.Product{
+				Actions: []string{"read", "write", "delete"},
+				Roles: []fake.Role{
+					{Name: "viewer", Description: "Viewer", Actions: []string{"pruned-product.read", "pruned-product.write"}},
+				},
+			})
+
+			typeNamespacedName := types.NamespacedName{Name: "pruned-resource", Namespace: "default"}
+			resource := &operatorv1alpha1.RoleActionConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: typeNamespacedName.Name, Namespace: typeNamespacedName.Namespace},
+				Spec: operatorv1alpha1.RoleActionConfigSpec{
+					ServiceID:      "pruned-product",
+					AccountIAMRef:  &operatorv1alpha1.AccountIAMReference{Name: "pruned-accountiam"},
+					DeletionPolicy: operatorv1alpha1.DeletionPolicyOrphan,
+					IAM: operatorv1alpha1.IAM{
+						Actions: []string{"read"},
+						V2CustomRoles: []operatorv1alpha1.V2CustomRoles{
+							{Name: "viewer", Description: "Viewer", Actions: []string{"read"}},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			})
+
+			maxDeletions := intstr.FromInt32(1)
+			controllerReconciler.Safeguard = iamsync.Safeguard{MaxDeletions: &maxDeletions}
+
+			By("Reconciling a spec removing two of the three product actions")
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			product, _ := fakeIAM.Product("pruned-product")
+			Expect(product.Actions).To(ConsistOf("read", "write", "delete"))
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			blocked := meta.FindStatusCondition(resource.Status.Conditions, operatorv1alpha1.ConditionTypeBlocked)
+			Expect(blocked).NotTo(BeNil())
+			Expect(blocked.Status).To(Equal(metav1.ConditionTrue))
+			Expect(blocked.Message).To(ContainSubstring("RemoveProductAction delete"))
+			Expect(blocked.Message).To(ContainSubstring(operatorv1alpha1.AcknowledgePruningAnnotation))
+			ready := meta.FindStatusCondition(resource.Status.Conditions, operatorv1alpha1.ConditionTypeReady)
+			Expect(ready).NotTo(BeNil())
+			Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+			Expect(ready.Reason).To(Equal(operatorv1alpha1.ConditionReasonPruneBlocked))
+
+			By("Acknowledging the pruning of the current generation")
+			resource.Annotations = map[string]string{
+				operatorv1alpha1.AcknowledgePruningAnnotation: strconv.FormatInt(resource.Generation, 10),
+			}
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			product, _ = fakeIAM.Product("pruned-product")
+			Expect(product.Actions).To(ConsistOf("read"))
+			Expect(product.Roles).To(HaveLen(1))
+			Expect(product.Roles[0].Actions).To(ConsistOf("pruned-product.read"))
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(meta.FindStatusCondition(resource.Status.Conditions, operatorv1alpha1.ConditionTypeBlocked)).To(BeNil())
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, operatorv1alpha1.ConditionTypeReady)).To(BeTrue())
+		})
+	})
+
+	Context("When deleting a resource", func() {
+		ctx := context.Background()
+
+		It("should release the finalizer without cleanup when the deletion policy is Orphan", func() {
+			typeNamespacedName := types.NamespacedName{Name: "orphan-resource", Namespace: "default"}
+			resource := &operatorv1alpha1.RoleActionConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       typeNamespacedName.Name,
+					Namespace:  typeNamespacedName.Namespace,
+					Finalizers: []string{resources.RoleActionConfigFinalizer},
+				},
+				Spec: operatorv1alpha1.RoleActionConfigSpec{
+					ServiceID:      "orphan-service",
+					DeletionPolicy: operatorv1alpha1.DeletionPolicyOrphan,
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+
+			By("Reconciling the deleted resource")
+			controllerReconciler := &RoleActionConfigReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking the resource is gone")
+			err = k8sClient.Get(ctx, typeNamespacedName, &operatorv1alpha1.RoleActionConfig{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
 	})
 })
@@ -40,6 +40,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/equality"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -210,6 +211,40 @@ func GetRedisInfo(redisURL string) (string, string, error) {
 	return hostname, port, nil
 }
 
+// SetDeploymentWorkload sets the replicas and the container resources of a Deployment object.
+// Objects of any other kind are left untouched.
+func SetDeploymentWorkload(obj *unstructured.Unstructured, replicas int32, res *corev1.ResourceRequirements) error {
+	if obj.GetKind() != "Deployment" {
+		return nil
+	}
+
+	if err := unstructured.SetNestedField(obj.Object, int64(replicas), "spec", "replicas"); err != nil {
+		return err
+	}
+
+	if res == nil {
+		return nil
+	}
+	resourceMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(res)
+	if err != nil {
+		return err
+	}
+
+	containers, found, err := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+	if err != nil || !found {
+		return err
+	}
+	for i := range containers {
+		container, ok := containers[i].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		container["resources"] = runtime.DeepCopyJSON(resourceMap)
+		containers[i] = container
+	}
+	return unstructured.SetNestedSlice(obj.Object, containers, "spec", "template", "spec", "containers")
+}
+
 // CalculateHashes calculates the hash for the existing cluster resource and the new template resource
 func CalculateHashes(fromCluster *unstructured.Unstructured, fromTemplate *unstructured.Unstructured) (string, string, error) {
 
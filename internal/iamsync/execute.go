@@ -0,0 +1,272 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iamsync
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	operatorv1alpha1 "github.com/IBM/ibm-user-management-operator/api/v1alpha1"
+	"github.com/IBM/ibm-user-management-operator/client/account_iam"
+	"github.com/IBM/ibm-user-management-operator/internal/retry"
+)
+
+var log = logf.Log.WithName("iamsync")
+
+// Executor applies the plans of a RoleActionConfig to its product in Account IAM
+type Executor struct {
+	Client   account_iam.IAMClient
+	Instance *operatorv1alpha1.RoleActionConfig
+}
+
+// Execute applies ops to the product in state. A failed operation does not stop the execution,
+// only the operations depending on it are skipped. Execute returns the state of the product
+// once the operations are applied, the outcome of each custom role the operations changed
+// by name, and the errors of the failed operations.
+func (e *Executor) Execute(ctx context.Context, state *State, ops []Operation) (*State, map[string]string, []error) {
+	serviceID := e.Instance.Spec.ServiceID
+	result := state.DeepCopy()
+	outcomes := map[string]string{}
+	var errs []error
+	rolesRefreshed := false
+
+	for _, op := range ops {
+		if !result.ProductRegistered && op.Kind != CreateProduct {
+			// nothing can be changed in a product Account IAM does not know
+			recordOutcome(outcomes, op, false)
+			continue
+		}
+
+		var roleUID string
+		if op.Role != "" && op.Kind != CreateRole {
+			role, ok := result.Roles[op.Role]
+			if !ok {
+				// the creation of the role failed
+				recordOutcome(outcomes, op, false)
+				continue
+			}
+			if role.UID == "" && !rolesRefreshed {
+				// learn the UIDs of the roles created without one in the response
+				rolesRefreshed = true
+				if err := e.refreshRoleUIDs(ctx, result); err != nil {
+					errs = append(errs, err)
+				}
+			}
+			if roleUID = role.UID; roleUID == "" {
+				recordOutcome(outcomes, op, false)
+				continue
+			}
+		}
+
+		created, err := e.apply(ctx, op, roleUID)
+		if err != nil {
+			log.Error(err, "Failed to apply operation", "operation", op.String(), "product", serviceID)
+			errs = append(errs, err)
+			recordOutcome(outcomes, op, false)
+			continue
+		}
+		log.Info("Applied operation", "operation", op.String(), "product", serviceID)
+		result.apply(op)
+		if created != nil {
+			result.Roles[op.Role].UID = created.UID
+			if created.BindableLevel != "" {
+				result.Roles[op.Role].BindableLevel = created.BindableLevel
+			}
+		}
+		recordOutcome(outcomes, op, true)
+	}
+
+	if !rolesRefreshed {
+		for _, role := range result.Roles {
+			if role.UID == "" {
+				if err := e.refreshRoleUIDs(ctx, result); err != nil {
+					errs = append(errs, err)
+				}
+				break
+			}
+		}
+	}
+
+	return result, outcomes, errs
+}
+
+// recordOutcome records how an operation changed its custom role. A role stays failed once one of its operations failed.
+func recordOutcome(outcomes map[string]string, op Operation, applied bool) {
+	if op.Role == "" || outcomes[op.Role] == operatorv1alpha1.RoleOutcomeFailed {
+		return
+	}
+	switch {
+	case !applied:
+		outcomes[op.Role] = operatorv1alpha1.RoleOutcomeFailed
+	case op.Kind == CreateRole:
+		outcomes[op.Role] = operatorv1alpha1.RoleOutcomeCreated
+	case op.Kind == DeleteRole:
+		outcomes[op.Role] = operatorv1alpha1.RoleOutcomeDeleted
+	case outcomes[op.Role] != operatorv1alpha1.RoleOutcomeCreated:
+		outcomes[op.Role] = operatorv1alpha1.RoleOutcomeUpdated
+	}
+}
+
+// apply sends the request of an operation to Account IAM. It returns the custom role created by a
+// CreateRole, or nil when the response does not carry its UID.
+func (e *Executor) apply(ctx context.Context, op Operation, roleUID string) (*account_iam.Role, error) {
+	serviceID := e.Instance.Spec.ServiceID
+
+	switch op.Kind {
+	case CreateProduct:
+		metadata := account_iam.ProductMetadata{DisplayName: op.DisplayName, Description: op.Description}
+		_, statusCode, err := e.Client.PostNewProduct(ctx, serviceID, metadata)
+		if err = checkStatus("POST", statusCode, err); err != nil {
+			return nil, fmt.Errorf("failed to register product %s: %w", serviceID, err)
+		}
+	case UpdateProduct:
+		metadata := account_iam.ProductMetadata{DisplayName: op.DisplayName, Description: op.Description}
+		if _, _, err := e.Client.UpdateProduct(ctx, serviceID, metadata); err != nil {
+			return nil, fmt.Errorf("failed to update product %s: %w", serviceID, err)
+		}
+	case AddProductAction:
+		if _, _, err := e.Client.PostActionsProductLevel(ctx, op.Action, op.Description, serviceID); err != nil {
+			return nil, fmt.Errorf("failed to create action %s: %w", op.Action, err)
+		}
+	case UpdateProductAction:
+		if _, _, err := e.Client.UpdateActionProductLevel(ctx, serviceID, op.Action, op.Description); err != nil {
+			return nil, fmt.Errorf("failed to update action %s: %w", op.Action, err)
+		}
+	case CreateRole:
+		role := op.customRole()
+		created, _, err := e.Client.PostCustomRoles(ctx, role, serviceID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create custom role %s: %w", op.Role, err)
+		}
+		if created == nil || created.UID == "" {
+			return nil, nil
+		}
+		return created, nil
+	case UpdateRole:
+		role := op.customRole()
+		if _, _, err := e.Client.UpdateCustomRoles(ctx, role, serviceID, roleUID); err != nil {
+			return nil, fmt.Errorf("failed to update custom role %s: %w", op.Role, err)
+		}
+	case AddRoleAction:
+		if _, _, err := e.Client.PostActionsRoleLevel(ctx, op.Action, roleUID, serviceID); err != nil {
+			return nil, fmt.Errorf("failed to add action %s to custom role %s: %w", op.Action, op.Role, err)
+		}
+	case RemoveRoleAction:
+		if _, _, err := e.Client.DeleteActionsRoleLevel(ctx, serviceID, roleUID, op.Action); err != nil {
+			return nil, fmt.Errorf("failed to remove action %s from custom role %s: %w", op.Action, op.Role, err)
+		}
+	case DeleteRole:
+		if _, _, err := e.Client.DeleteCustomRoles(ctx, e.Instance, roleUID); err != nil {
+			return nil, fmt.Errorf("failed to delete custom role %s: %w", op.Role, err)
+		}
+	case RemoveProductAction:
+		if _, _, err := e.Client.DeleteActionsProductLevel(ctx, serviceID, op.Action); err != nil {
+			return nil, fmt.Errorf("failed to delete action %s: %w", op.Action, err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown operation %s", op.Kind)
+	}
+	return nil, nil
+}
+
+// customRole returns the custom role a CreateRole or UpdateRole sends to Account IAM
+func (o Operation) customRole() operatorv1alpha1.V2CustomRoles {
+	return operatorv1alpha1.V2CustomRoles{
+		Name:          o.Role,
+		Description:   o.Description,
+		DisplayName:   o.DisplayName,
+		BindableLevel: o.BindableLevel,
+	}
+}
+
+// refreshRoleUIDs records the UIDs Account IAM gave to the roles of state
+func (e *Executor) refreshRoleUIDs(ctx context.Context, state *State) error {
+	serviceID := e.Instance.Spec.ServiceID
+	roles, _, err := e.Client.GetRoles(ctx, serviceID)
+	if err != nil {
+		return fmt.Errorf("failed to get custom roles of product %s: %w", serviceID, err)
+	}
+	for _, role := range roles {
+		if roleState, ok := state.Roles[role.Name]; ok {
+			roleState.UID = role.UID
+			roleState.BindableLevel = role.BindableLevel
+		}
+	}
+	return nil
+}
+
+// apply records a successful operation in the state
+func (s *State) apply(op Operation) {
+	switch op.Kind {
+	case CreateProduct:
+		s.ProductRegistered = true
+		s.DisplayName, s.Description = op.DisplayName, op.Description
+	case UpdateProduct:
+		s.DisplayName = keep(op.DisplayName, s.DisplayName)
+		s.Description = keep(op.Description, s.Description)
+	case AddProductAction:
+		s.Actions.Insert(op.Action)
+		if op.Description != "" {
+			s.ActionDescriptions[op.Action] = op.Description
+		}
+	case UpdateProductAction:
+		s.ActionDescriptions[op.Action] = op.Description
+	case RemoveProductAction:
+		s.Actions.Delete(op.Action)
+		delete(s.ActionDescriptions, op.Action)
+	case CreateRole:
+		s.Roles[op.Role] = &RoleState{
+			Description:   op.Description,
+			DisplayName:   op.DisplayName,
+			BindableLevel: op.BindableLevel,
+			Actions:       sets.New[string](),
+		}
+	case UpdateRole:
+		role := s.Roles[op.Role]
+		role.Description = keep(op.Description, role.Description)
+		role.DisplayName = keep(op.DisplayName, role.DisplayName)
+		role.BindableLevel = keep(op.BindableLevel, role.BindableLevel)
+	case AddRoleAction:
+		s.Roles[op.Role].Actions.Insert(op.Action)
+	case RemoveRoleAction:
+		s.Roles[op.Role].Actions.Delete(op.Action)
+	case DeleteRole:
+		delete(s.Roles, op.Role)
+	}
+}
+
+// keep returns the updated value, or the current one when the update leaves it empty
+func keep(updated string, current string) string {
+	if updated == "" {
+		return current
+	}
+	return updated
+}
+
+// checkStatus turns the client errors of the calls which do not fail on them into permanent errors
+func checkStatus(method string, statusCode int, err error) error {
+	if err != nil {
+		return err
+	}
+	if statusCode >= 400 && statusCode < 500 {
+		return retry.NewPermanentError(fmt.Errorf("%s returned client error: %d", method, statusCode))
+	}
+	return nil
+}
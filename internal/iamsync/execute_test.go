@@ -0,0 +1,175 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iamsync
+
+import (
+	"context"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	operatorv1alpha1 "github.com/IBM/ibm-user-management-operator/api/v1alpha1"
+	"github.com/IBM/ibm-user-management-operator/client/account_iam"
+	"github.com/IBM/ibm-user-management-operator/client/account_iam/fake"
+	"github.com/IBM/ibm-user-management-operator/internal/retry"
+)
+
+var _ = Describe("Executor", func() {
+	ctx := context.Background()
+
+	var fakeIAM *fake.Server
+	var client account_iam.IAMClient
+
+	BeforeEach(func() {
+		fakeIAM = fake.NewServer(fake.WithPageSize(1))
+		DeferCleanup(fakeIAM.Close)
+
+		factory := account_iam.NewClientFactory(&retry.Retry{}, account_iam.FactoryOptions{
+			InsecureSkipVerify: true,
+			ServiceURL:         func(string) string { return fakeIAM.URL },
+		})
+		var err error
+		client, err = factory.ClientFor("accountiam-uid", account_iam.ClientConfig{Namespace: "default", APIKey: "api-key"})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.GetToken(ctx, factory.TokenURL("default"))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	sync := func(spec operatorv1alpha1.RoleActionConfigSpec) (*State, map[string]string, []error) {
+		instance := &operatorv1alpha1.RoleActionConfig{ObjectMeta: metav1.ObjectMeta{Name: "product"}, Spec: spec}
+		state, err := Observe(ctx, client, spec.ServiceID)
+		Expect(err).NotTo(HaveOccurred())
+		executor := &Executor{Client: client, Instance: instance}
+		return executor.Execute(ctx, state, Plan(spec, state))
+	}
+
+	It("should converge the product to the spec", func() {
+		spec := operatorv1alpha1.RoleActionConfigSpec{
+			ServiceID: "product",
+			IAM: operatorv1alpha1.IAM{
+				Actions: []string{"read", "write"},
+				V2CustomRoles: []operatorv1alpha1.V2CustomRoles{
+					{Name: "viewer", Description: "Viewer", Actions: []string{"read"}},
+					{Name: "editor", Description: "Editor", Actions: []string{"read", "write"}},
+				},
+			},
+		}
+
+		By("registering the product")
+		state, outcomes, errs := sync(spec)
+		Expect(errs).To(BeEmpty())
+		Expect(outcomes).To(Equal(map[string]string{
+			"viewer": operatorv1alpha1.RoleOutcomeCreated,
+			"editor": operatorv1alpha1.RoleOutcomeCreated,
+		}))
+		Expect(state.Roles["viewer"].UID).NotTo(BeEmpty())
+		Expect(sets.List(state.Roles["editor"].Actions)).To(Equal([]string{"product.read", "product.write"}))
+
+		product, ok := fakeIAM.Product("product")
+		Expect(ok).To(BeTrue())
+		Expect(product.Actions).To(Equal([]string{"read", "write"}))
+		Expect(product.Roles).To(HaveLen(2))
+
+		By("dropping a role and an action from the spec")
+		spec.IAM.Actions = []string{"read"}
+		spec.IAM.V2CustomRoles = spec.IAM.V2CustomRoles[:1]
+		_, outcomes, errs = sync(spec)
+		Expect(errs).To(BeEmpty())
+		Expect(outcomes).To(Equal(map[string]string{"editor": operatorv1alpha1.RoleOutcomeDeleted}))
+
+		product, _ = fakeIAM.Product("product")
+		Expect(product.Actions).To(Equal([]string{"read"}))
+		Expect(product.Roles).To(HaveLen(1))
+		Expect(product.Roles[0].Name).To(Equal("viewer"))
+		Expect(product.Roles[0].Actions).To(Equal([]string{"product.read"}))
+
+		By("syncing again without changes")
+		state, _ = Observe(ctx, client, spec.ServiceID)
+		Expect(Plan(spec, state)).To(BeEmpty())
+	})
+
+	It("should register the metadata of the product, its actions and roles", func() {
+		spec := operatorv1alpha1.RoleActionConfigSpec{
+			ServiceID:   "product",
+			DisplayName: "Product",
+			Description: "A product",
+			IAM: operatorv1alpha1.IAM{
+				Actions:            []string{"read"},
+				ActionDescriptions: map[string]string{"read": "Read the resources"},
+				V2CustomRoles: []operatorv1alpha1.V2CustomRoles{
+					{Name: "viewer", Description: "Viewer", DisplayName: "Viewer", BindableLevel: "ACCOUNT", Actions: []string{"read"}},
+				},
+			},
+		}
+		_, _, errs := sync(spec)
+		Expect(errs).To(BeEmpty())
+
+		product, _ := fakeIAM.Product("product")
+		Expect(product.DisplayName).To(Equal("Product"))
+		Expect(product.Description).To(Equal("A product"))
+		Expect(product.ActionDescriptions).To(Equal(map[string]string{"read": "Read the resources"}))
+		Expect(product.Roles[0].DisplayName).To(Equal("Viewer"))
+		Expect(product.Roles[0].BindableLevel).To(Equal("ACCOUNT"))
+
+		By("updating the metadata")
+		spec.Description = "The product"
+		spec.IAM.ActionDescriptions["read"] = "Read"
+		_, _, errs = sync(spec)
+		Expect(errs).To(BeEmpty())
+
+		product, _ = fakeIAM.Product("product")
+		Expect(product.Description).To(Equal("The product"))
+		Expect(product.ActionDescriptions).To(Equal(map[string]string{"read": "Read"}))
+
+		By("syncing again without changes")
+		state, err := Observe(ctx, client, spec.ServiceID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(Plan(spec, state)).To(BeEmpty())
+	})
+
+	It("should only change the roles which differ, with the UID returned on creation", func() {
+		spec := operatorv1alpha1.RoleActionConfigSpec{
+			ServiceID: "product",
+			IAM: operatorv1alpha1.IAM{
+				Actions: []string{"read"},
+				V2CustomRoles: []operatorv1alpha1.V2CustomRoles{
+					{Name: "viewer", Description: "Viewer", Actions: []string{"read"}},
+					{Name: "auditor", Description: "Auditor", Actions: []string{"read"}},
+				},
+			},
+		}
+		rolesPath := account_iam.ProductsPath + "/product/roles"
+
+		By("creating the roles without listing them again")
+		state, _, errs := sync(spec)
+		Expect(errs).To(BeEmpty())
+		Expect(fakeIAM.Requests(http.MethodGet, rolesPath)).To(BeZero())
+		Expect(state.Roles["viewer"].BindableLevel).To(Equal("SERVICE"))
+
+		By("changing the description of a single role")
+		spec.IAM.V2CustomRoles[0].Description = "Read only"
+		_, outcomes, errs := sync(spec)
+		Expect(errs).To(BeEmpty())
+		Expect(outcomes).To(Equal(map[string]string{"viewer": operatorv1alpha1.RoleOutcomeUpdated}))
+		Expect(fakeIAM.Requests(http.MethodPatch, rolesPath+"/"+state.Roles["viewer"].UID)).To(Equal(1))
+		Expect(fakeIAM.Requests(http.MethodPatch, rolesPath+"/"+state.Roles["auditor"].UID)).To(BeZero())
+		Expect(fakeIAM.Requests(http.MethodPost, rolesPath)).To(Equal(2))
+	})
+})
@@ -0,0 +1,85 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iamsync
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/IBM/ibm-user-management-operator/client/account_iam"
+	"github.com/IBM/ibm-user-management-operator/internal/retry"
+)
+
+// Observe takes a snapshot of a product in Account IAM. A product unknown to Account IAM has an empty state.
+func Observe(ctx context.Context, client account_iam.IAMClient, serviceID string) (*State, error) {
+	state := NewState()
+
+	product, statusCode, err := client.GetProductDetails(ctx, serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product %s: %w", serviceID, err)
+	}
+	switch {
+	case statusCode == http.StatusNotFound:
+		return state, nil
+	case statusCode >= 400 && statusCode < 500:
+		return nil, retry.NewPermanentError(fmt.Errorf("failed to get product %s: GET returned client error: %d", serviceID, statusCode))
+	case statusCode != http.StatusOK:
+		return nil, fmt.Errorf("failed to get product %s: GET returned %d", serviceID, statusCode)
+	}
+	state.ProductRegistered = true
+	if product != nil {
+		state.DisplayName, state.Description = product.DisplayName, product.Description
+	}
+
+	actions, _, err := client.GetActionsProductLevel(ctx, serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list actions of product %s: %w", serviceID, err)
+	}
+	for _, action := range actions {
+		state.Actions.Insert(action.Name)
+		if action.Description != "" {
+			state.ActionDescriptions[action.Name] = action.Description
+		}
+	}
+
+	roles, _, err := client.GetRoles(ctx, serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get custom roles of product %s: %w", serviceID, err)
+	}
+	for _, role := range roles {
+		roleActions, _, err := client.GetActionsRoleLevel(ctx, serviceID, role.UID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list actions of custom role %s: %w", role.Name, err)
+		}
+		roleState := &RoleState{
+			UID:           role.UID,
+			Description:   role.Description,
+			DisplayName:   role.DisplayName,
+			BindableLevel: role.BindableLevel,
+			Actions:       sets.New[string](),
+		}
+		for _, action := range roleActions {
+			roleState.Actions.Insert(action.Name)
+		}
+		state.Roles[role.Name] = roleState
+	}
+
+	return state, nil
+}
@@ -0,0 +1,267 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package iamsync synchronizes the products of RoleActionConfigs with Account IAM in three steps:
+// Observe takes a snapshot of the product in Account IAM, Plan diffs it with the spec into an
+// ordered list of operations, and an Executor applies them.
+package iamsync
+
+import (
+	"fmt"
+	"net/http"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	operatorv1alpha1 "github.com/IBM/ibm-user-management-operator/api/v1alpha1"
+	"github.com/IBM/ibm-user-management-operator/client/account_iam"
+)
+
+// OperationKind is the kind of change an Operation makes in Account IAM
+type OperationKind string
+
+// The kinds of operations, in the order a plan applies them. Product actions are added before
+// the roles referencing them, and removed once no role references them anymore.
+const (
+	CreateProduct       OperationKind = "CreateProduct"
+	UpdateProduct       OperationKind = "UpdateProduct"
+	AddProductAction    OperationKind = "AddProductAction"
+	UpdateProductAction OperationKind = "UpdateProductAction"
+	CreateRole          OperationKind = "CreateRole"
+	UpdateRole          OperationKind = "UpdateRole"
+	AddRoleAction       OperationKind = "AddRoleAction"
+	RemoveRoleAction    OperationKind = "RemoveRoleAction"
+	DeleteRole          OperationKind = "DeleteRole"
+	RemoveProductAction OperationKind = "RemoveProductAction"
+)
+
+// Operation is a change to make in Account IAM
+type Operation struct {
+	Kind OperationKind
+	// Role is the name of the custom role the operation applies to
+	Role string
+	// Description is the description of the product, action or custom role to create or update
+	Description string
+	// DisplayName is the display name of the product or custom role to create or update
+	DisplayName string
+	// BindableLevel is the bindable level of the custom role to create or update
+	BindableLevel string
+	// Action is the action to add, update or remove. The role level actions carry the service ID prefix.
+	Action string
+}
+
+func (o Operation) String() string {
+	switch o.Kind {
+	case CreateProduct, UpdateProduct:
+		return string(o.Kind)
+	case AddProductAction, UpdateProductAction, RemoveProductAction:
+		return fmt.Sprintf("%s %s", o.Kind, o.Action)
+	case AddRoleAction:
+		return fmt.Sprintf("%s %s to role %s", o.Kind, o.Action, o.Role)
+	case RemoveRoleAction:
+		return fmt.Sprintf("%s %s from role %s", o.Kind, o.Action, o.Role)
+	}
+	return fmt.Sprintf("%s %s", o.Kind, o.Role)
+}
+
+// Request returns the method and path of the request sending the operation to Account IAM for
+// the product of serviceID in state. The UID of a role missing from state is written as {role name}.
+func (o Operation) Request(serviceID string, state *State) string {
+	productPath := account_iam.ProductsPath + "/" + serviceID
+	rolePath := productPath + "/roles/{" + o.Role + "}"
+	if role, ok := state.Roles[o.Role]; ok && role.UID != "" {
+		rolePath = productPath + "/roles/" + role.UID
+	}
+
+	switch o.Kind {
+	case CreateProduct:
+		return http.MethodPost + " " + productPath
+	case UpdateProduct:
+		return http.MethodPatch + " " + productPath
+	case AddProductAction:
+		return http.MethodPost + " " + productPath + "/actions"
+	case UpdateProductAction:
+		return http.MethodPatch + " " + productPath + "/actions/" + o.Action
+	case RemoveProductAction:
+		return http.MethodDelete + " " + productPath + "/actions/" + o.Action
+	case CreateRole:
+		return http.MethodPost + " " + productPath + "/roles"
+	case UpdateRole:
+		return http.MethodPatch + " " + rolePath
+	case AddRoleAction:
+		return http.MethodPost + " " + rolePath + "/actions"
+	case RemoveRoleAction:
+		return http.MethodDelete + " " + rolePath + "/actions/" + o.Action
+	case DeleteRole:
+		return http.MethodDelete + " " + rolePath
+	}
+	return string(o.Kind)
+}
+
+// State is a snapshot of a product in Account IAM
+type State struct {
+	// ProductRegistered tells whether the product is known to Account IAM
+	ProductRegistered bool
+	// DisplayName and Description describe the product
+	DisplayName string
+	Description string
+	// Actions are the product level actions
+	Actions sets.Set[string]
+	// ActionDescriptions are the descriptions of the product level actions which have one
+	ActionDescriptions map[string]string
+	// Roles are the custom roles, by name
+	Roles map[string]*RoleState
+}
+
+// RoleState is a snapshot of a custom role in Account IAM
+type RoleState struct {
+	UID           string
+	Description   string
+	DisplayName   string
+	BindableLevel string
+	// Actions are the role level actions, with the service ID prefix
+	Actions sets.Set[string]
+}
+
+// NewState returns the state of a product unknown to Account IAM
+func NewState() *State {
+	return &State{
+		Actions:            sets.New[string](),
+		ActionDescriptions: map[string]string{},
+		Roles:              map[string]*RoleState{},
+	}
+}
+
+// DeepCopy returns a copy of the state sharing nothing with it
+func (s *State) DeepCopy() *State {
+	out := &State{
+		ProductRegistered:  s.ProductRegistered,
+		DisplayName:        s.DisplayName,
+		Description:        s.Description,
+		Actions:            s.Actions.Clone(),
+		ActionDescriptions: make(map[string]string, len(s.ActionDescriptions)),
+		Roles:              make(map[string]*RoleState, len(s.Roles)),
+	}
+	for action, description := range s.ActionDescriptions {
+		out.ActionDescriptions[action] = description
+	}
+	for name, role := range s.Roles {
+		out.Roles[name] = &RoleState{
+			UID:           role.UID,
+			Description:   role.Description,
+			DisplayName:   role.DisplayName,
+			BindableLevel: role.BindableLevel,
+			Actions:       role.Actions.Clone(),
+		}
+	}
+	return out
+}
+
+// differs reports whether a value set in the spec differs from the one in Account IAM
+func differs(desired string, current string) bool {
+	return desired != "" && desired != current
+}
+
+// RoleActionName returns the name of a role level action in Account IAM
+func RoleActionName(serviceID string, action string) string {
+	return serviceID + "." + action
+}
+
+// Plan returns the operations bringing the product in state to the spec, in the order they must be applied.
+// The display names, descriptions and bindable levels left empty in the spec are not updated. Unset product
+// actions, custom roles or role actions are left as they are in Account IAM, only an empty list removes them.
+func Plan(spec operatorv1alpha1.RoleActionConfigSpec, state *State) []Operation {
+	var ops []Operation
+
+	if !state.ProductRegistered {
+		ops = append(ops, Operation{Kind: CreateProduct, DisplayName: spec.DisplayName, Description: spec.Description})
+	} else if differs(spec.DisplayName, state.DisplayName) || differs(spec.Description, state.Description) {
+		ops = append(ops, Operation{Kind: UpdateProduct, DisplayName: spec.DisplayName, Description: spec.Description})
+	}
+
+	desiredActions := sets.New(spec.IAM.Actions...)
+	if spec.IAM.Actions != nil {
+		for _, action := range sets.List(desiredActions.Difference(state.Actions)) {
+			ops = append(ops, Operation{Kind: AddProductAction, Action: action, Description: spec.IAM.ActionDescriptions[action]})
+		}
+		for _, action := range sets.List(desiredActions.Intersection(state.Actions)) {
+			if description := spec.IAM.ActionDescriptions[action]; differs(description, state.ActionDescriptions[action]) {
+				ops = append(ops, Operation{Kind: UpdateProductAction, Action: action, Description: description})
+			}
+		}
+	}
+
+	desiredRoles := map[string]operatorv1alpha1.V2CustomRoles{}
+	for _, role := range spec.IAM.V2CustomRoles {
+		desiredRoles[role.Name] = role
+	}
+	roleNames := sets.KeySet(desiredRoles)
+
+	var createOps, updateOps, addActionOps, removeActionOps, deleteOps []Operation
+	for _, name := range sets.List(roleNames) {
+		role := desiredRoles[name]
+		current, ok := state.Roles[name]
+		roleOp := Operation{Role: name, Description: role.Description, DisplayName: role.DisplayName, BindableLevel: role.BindableLevel}
+		switch {
+		case !ok:
+			roleOp.Kind = CreateRole
+			createOps = append(createOps, roleOp)
+			current = &RoleState{Actions: sets.New[string]()}
+		case differs(role.Description, current.Description) || differs(role.DisplayName, current.DisplayName) ||
+			// a bindable level Account IAM does not report is left as is
+			(current.BindableLevel != "" && differs(role.BindableLevel, current.BindableLevel)):
+			roleOp.Kind = UpdateRole
+			updateOps = append(updateOps, roleOp)
+		}
+
+		if role.Actions == nil {
+			continue
+		}
+		desiredRoleActions := sets.New[string]()
+		for _, action := range role.Actions {
+			desiredRoleActions.Insert(RoleActionName(spec.ServiceID, action))
+		}
+		for _, action := range sets.List(desiredRoleActions.Difference(current.Actions)) {
+			addActionOps = append(addActionOps, Operation{Kind: AddRoleAction, Role: name, Action: action})
+		}
+		for _, action := range sets.List(current.Actions.Difference(desiredRoleActions)) {
+			removeActionOps = append(removeActionOps, Operation{Kind: RemoveRoleAction, Role: name, Action: action})
+		}
+	}
+
+	// The roles removed from the spec are emptied before they are deleted
+	if spec.IAM.V2CustomRoles != nil {
+		for _, name := range sets.List(sets.KeySet(state.Roles).Difference(roleNames)) {
+			for _, action := range sets.List(state.Roles[name].Actions) {
+				removeActionOps = append(removeActionOps, Operation{Kind: RemoveRoleAction, Role: name, Action: action})
+			}
+			deleteOps = append(deleteOps, Operation{Kind: DeleteRole, Role: name})
+		}
+	}
+
+	ops = append(ops, createOps...)
+	ops = append(ops, updateOps...)
+	ops = append(ops, addActionOps...)
+	ops = append(ops, removeActionOps...)
+	ops = append(ops, deleteOps...)
+
+	if spec.IAM.Actions != nil {
+		for _, action := range sets.List(state.Actions.Difference(desiredActions)) {
+			ops = append(ops, Operation{Kind: RemoveProductAction, Action: action})
+		}
+	}
+
+	return ops
+}
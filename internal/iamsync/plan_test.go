@@ -0,0 +1,196 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iamsync
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	operatorv1alpha1 "github.com/IBM/ibm-user-management-operator/api/v1alpha1"
+)
+
+var _ = Describe("Plan", func() {
+	spec := operatorv1alpha1.RoleActionConfigSpec{
+		ServiceID: "product",
+		IAM: operatorv1alpha1.IAM{
+			Actions: []string{"read", "write"},
+			V2CustomRoles: []operatorv1alpha1.V2CustomRoles{
+				{Name: "viewer", Description: "Viewer", Actions: []string{"read"}},
+				{Name: "editor", Description: "Editor", Actions: []string{"read", "write"}},
+			},
+		},
+	}
+
+	It("should register a new product before its actions and roles", func() {
+		Expect(Plan(spec, NewState())).To(Equal([]Operation{
+			{Kind: CreateProduct},
+			{Kind: AddProductAction, Action: "read"},
+			{Kind: AddProductAction, Action: "write"},
+			{Kind: CreateRole, Role: "editor", Description: "Editor"},
+			{Kind: CreateRole, Role: "viewer", Description: "Viewer"},
+			{Kind: AddRoleAction, Role: "editor", Action: "product.read"},
+			{Kind: AddRoleAction, Role: "editor", Action: "product.write"},
+			{Kind: AddRoleAction, Role: "viewer", Action: "product.read"},
+		}))
+	})
+
+	It("should plan nothing for a product in sync", func() {
+		state := &State{
+			ProductRegistered: true,
+			Actions:           sets.New("read", "write"),
+			Roles: map[string]*RoleState{
+				"viewer": {UID: "1", Description: "Viewer", Actions: sets.New("product.read")},
+				"editor": {UID: "2", Description: "Editor", Actions: sets.New("product.read", "product.write")},
+			},
+		}
+		Expect(Plan(spec, state)).To(BeEmpty())
+	})
+
+	It("should remove what the spec dropped once nothing references it", func() {
+		state := &State{
+			ProductRegistered: true,
+			Actions:           sets.New("read", "write", "delete"),
+			Roles: map[string]*RoleState{
+				"viewer": {UID: "1", Description: "Old viewer", Actions: sets.New("product.read", "product.delete")},
+				"editor": {UID: "2", Description: "Editor", Actions: sets.New("product.read", "product.write")},
+				"admin":  {UID: "3", Description: "Admin", Actions: sets.New("product.delete")},
+			},
+		}
+		Expect(Plan(spec, state)).To(Equal([]Operation{
+			{Kind: UpdateRole, Role: "viewer", Description: "Viewer"},
+			{Kind: RemoveRoleAction, Role: "viewer", Action: "product.delete"},
+			{Kind: RemoveRoleAction, Role: "admin", Action: "product.delete"},
+			{Kind: DeleteRole, Role: "admin"},
+			{Kind: RemoveProductAction, Action: "delete"},
+		}))
+	})
+
+	It("should sync the role actions of a spec without product actions", func() {
+		spec := operatorv1alpha1.RoleActionConfigSpec{
+			ServiceID: "product",
+			IAM: operatorv1alpha1.IAM{
+				V2CustomRoles: []operatorv1alpha1.V2CustomRoles{{Name: "viewer", Description: "Viewer", Actions: []string{}}},
+			},
+		}
+		state := &State{
+			ProductRegistered: true,
+			Actions:           sets.New[string](),
+			Roles: map[string]*RoleState{
+				"viewer": {UID: "1", Description: "Viewer", Actions: sets.New("product.read")},
+			},
+		}
+		Expect(Plan(spec, state)).To(Equal([]Operation{
+			{Kind: RemoveRoleAction, Role: "viewer", Action: "product.read"},
+		}))
+	})
+
+	It("should leave the actions and roles unset in the spec unmanaged", func() {
+		state := &State{
+			ProductRegistered: true,
+			Actions:           sets.New("read"),
+			Roles: map[string]*RoleState{
+				"viewer": {UID: "1", Description: "Viewer", Actions: sets.New("product.read")},
+			},
+		}
+
+		By("leaving the product actions and roles when the spec has none")
+		spec := operatorv1alpha1.RoleActionConfigSpec{ServiceID: "product"}
+		Expect(Plan(spec, state)).To(BeEmpty())
+
+		By("leaving the role actions when the role has none")
+		spec.IAM.V2CustomRoles = []operatorv1alpha1.V2CustomRoles{{Name: "viewer", Description: "Viewer"}}
+		Expect(Plan(spec, state)).To(BeEmpty())
+
+		By("removing the role actions when the role has an empty list")
+		spec.IAM.V2CustomRoles[0].Actions = []string{}
+		Expect(Plan(spec, state)).To(Equal([]Operation{
+			{Kind: RemoveRoleAction, Role: "viewer", Action: "product.read"},
+		}))
+
+		By("removing the roles when the spec has an empty list")
+		spec.IAM.V2CustomRoles = []operatorv1alpha1.V2CustomRoles{}
+		Expect(Plan(spec, state)).To(Equal([]Operation{
+			{Kind: RemoveRoleAction, Role: "viewer", Action: "product.read"},
+			{Kind: DeleteRole, Role: "viewer"},
+		}))
+
+		By("removing the product actions when the spec has an empty list")
+		spec.IAM.Actions = []string{}
+		Expect(Plan(spec, state)).To(Equal([]Operation{
+			{Kind: RemoveRoleAction, Role: "viewer", Action: "product.read"},
+			{Kind: DeleteRole, Role: "viewer"},
+			{Kind: RemoveProductAction, Action: "read"},
+		}))
+	})
+
+	It("should only update the metadata set in the spec which differs", func() {
+		spec := operatorv1alpha1.RoleActionConfigSpec{
+			ServiceID:   "product",
+			DisplayName: "Product",
+			IAM: operatorv1alpha1.IAM{
+				Actions:            []string{"read", "write"},
+				ActionDescriptions: map[string]string{"read": "Read", "write": "Write"},
+				V2CustomRoles: []operatorv1alpha1.V2CustomRoles{
+					{Name: "viewer", Description: "Viewer", DisplayName: "Viewer", BindableLevel: "ACCOUNT", Actions: []string{"read"}},
+				},
+			},
+		}
+		state := &State{
+			ProductRegistered:  true,
+			DisplayName:        "Old product",
+			Description:        "Registered by hand",
+			Actions:            sets.New("read", "write"),
+			ActionDescriptions: map[string]string{"read": "Read"},
+			Roles: map[string]*RoleState{
+				"viewer": {UID: "1", Description: "Viewer", BindableLevel: "SERVICE", Actions: sets.New("product.read")},
+			},
+		}
+		Expect(Plan(spec, state)).To(Equal([]Operation{
+			{Kind: UpdateProduct, DisplayName: "Product"},
+			{Kind: UpdateProductAction, Action: "write", Description: "Write"},
+			{Kind: UpdateRole, Role: "viewer", Description: "Viewer", DisplayName: "Viewer", BindableLevel: "ACCOUNT"},
+		}))
+	})
+
+	It("should leave the role description of Account IAM when the spec has none", func() {
+		spec := operatorv1alpha1.RoleActionConfigSpec{
+			ServiceID: "product",
+			IAM: operatorv1alpha1.IAM{
+				Actions: []string{"read"},
+				V2CustomRoles: []operatorv1alpha1.V2CustomRoles{
+					{Name: "viewer", DisplayName: "Viewer", Actions: []string{"read"}},
+				},
+			},
+		}
+		state := &State{
+			ProductRegistered: true,
+			Actions:           sets.New("read"),
+			Roles: map[string]*RoleState{
+				"viewer": {UID: "1", Description: "Registered by hand", DisplayName: "Viewer", Actions: sets.New("product.read")},
+			},
+		}
+		Expect(Plan(spec, state)).To(BeEmpty())
+
+		By("updating the display name without clearing the description")
+		state.Roles["viewer"].DisplayName = "Old viewer"
+		ops := Plan(spec, state)
+		Expect(ops).To(Equal([]Operation{{Kind: UpdateRole, Role: "viewer", DisplayName: "Viewer"}}))
+		state.apply(ops[0])
+		Expect(state.Roles["viewer"].Description).To(Equal("Registered by hand"))
+	})
+})
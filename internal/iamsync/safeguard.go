@@ -0,0 +1,137 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iamsync
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// maxReportedPrunes is how many of the held back operations a Verdict describes by name
+const maxReportedPrunes = 10
+
+// Safeguard holds back the pruning of a plan removing more actions or custom roles than allowed, so a
+// typo or an emptied list in the spec does not wipe the actions and roles of a product from Account IAM
+type Safeguard struct {
+	// MaxDeletions is the count, or the percentage of the ones in Account IAM, of product level actions,
+	// of role level actions and of custom roles a plan may remove. A percentage is rounded down, but lets
+	// one of them be removed unless it is the only one. A nil MaxDeletions allows any deletion.
+	MaxDeletions *intstr.IntOrString
+}
+
+// Verdict is the outcome of checking a plan against a Safeguard
+type Verdict struct {
+	// Allowed are the operations of the plan to apply
+	Allowed []Operation
+	// Blocked are the pruning operations held back, empty when the plan is within the limit
+	Blocked []Operation
+	// ProductActions and RoleActions are how many actions the plan removes at each level
+	ProductActions int
+	RoleActions    int
+	// Roles is how many custom roles the plan deletes
+	Roles int
+}
+
+// Check splits the operations planned for the product in state into the ones to apply and the
+// pruning operations to hold back. Either all or none of the pruning operations are held back,
+// since deleting a role or a product action depends on removing its role actions first.
+func (s Safeguard) Check(state *State, ops []Operation) (Verdict, error) {
+	verdict := Verdict{Allowed: ops}
+	for _, op := range ops {
+		switch op.Kind {
+		case RemoveProductAction:
+			verdict.ProductActions++
+		case RemoveRoleAction:
+			verdict.RoleActions++
+		case DeleteRole:
+			verdict.Roles++
+		}
+	}
+	if s.MaxDeletions == nil {
+		return verdict, nil
+	}
+
+	roleActions := 0
+	for _, role := range state.Roles {
+		roleActions += role.Actions.Len()
+	}
+	maxProductActions, err := s.limit(state.Actions.Len())
+	if err != nil {
+		return verdict, err
+	}
+	maxRoleActions, err := s.limit(roleActions)
+	if err != nil {
+		return verdict, err
+	}
+	maxRoles, err := s.limit(len(state.Roles))
+	if err != nil {
+		return verdict, err
+	}
+	if verdict.ProductActions <= maxProductActions && verdict.RoleActions <= maxRoleActions && verdict.Roles <= maxRoles {
+		return verdict, nil
+	}
+
+	verdict.Allowed = nil
+	for _, op := range ops {
+		if isPrune(op) {
+			verdict.Blocked = append(verdict.Blocked, op)
+		} else {
+			verdict.Allowed = append(verdict.Allowed, op)
+		}
+	}
+	return verdict, nil
+}
+
+// limit returns how many of total actions or custom roles a plan may remove
+func (s Safeguard) limit(total int) (int, error) {
+	limit, err := intstr.GetScaledValueFromIntOrPercent(s.MaxDeletions, total, false)
+	if err != nil {
+		return 0, fmt.Errorf("invalid maximum of deletions: %w", err)
+	}
+	// A non-zero percentage of a few still lets one of them go, never the last one
+	if limit == 0 && total > 1 {
+		if roundedUp, _ := intstr.GetScaledValueFromIntOrPercent(s.MaxDeletions, total, true); roundedUp > 0 {
+			limit = 1
+		}
+	}
+	return limit, nil
+}
+
+// Describe returns what the held back operations would remove from Account IAM
+func (v Verdict) Describe() string {
+	names := make([]string, 0, maxReportedPrunes)
+	for i, op := range v.Blocked {
+		if i == maxReportedPrunes {
+			names = append(names, fmt.Sprintf("and %d more", len(v.Blocked)-maxReportedPrunes))
+			break
+		}
+		names = append(names, op.String())
+	}
+	return fmt.Sprintf("removing %d product level actions, %d role level actions and %d custom roles exceeds the limit of deletions: %s",
+		v.ProductActions, v.RoleActions, v.Roles, strings.Join(names, ", "))
+}
+
+// isPrune reports whether the operation removes something from Account IAM
+func isPrune(op Operation) bool {
+	switch op.Kind {
+	case RemoveRoleAction, DeleteRole, RemoveProductAction:
+		return true
+	}
+	return false
+}
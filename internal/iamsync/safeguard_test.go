@@ -0,0 +1,184 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iamsync
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	operatorv1alpha1 "github.com/IBM/ibm-user-management-operator/api/v1alpha1"
+)
+
+var _ = Describe("Safeguard", func() {
+	var state *State
+
+	// emptySpec removes every action and custom role of the product
+	emptySpec := operatorv1alpha1.RoleActionConfigSpec{
+		ServiceID: "product",
+		IAM:       operatorv1alpha1.IAM{Actions: []string{}, V2CustomRoles: []operatorv1alpha1.V2CustomRoles{}},
+	}
+
+	BeforeEach(func() {
+		state = &State{
+			ProductRegistered: true,
+			Actions:           sets.New("read", "write", "delete", "admin"),
+			Roles: map[string]*RoleState{
+				"viewer": {UID: "1", Description: "Viewer", Actions: sets.New("product.read")},
+				"editor": {UID: "2", Description: "Editor", Actions: sets.New("product.read", "product.write")},
+			},
+		}
+	})
+
+	It("should allow any deletion without a limit", func() {
+		ops := Plan(emptySpec, state)
+		verdict, err := Safeguard{}.Check(state, ops)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(verdict.Allowed).To(Equal(ops))
+		Expect(verdict.Blocked).To(BeEmpty())
+		Expect(verdict.ProductActions).To(Equal(4))
+		Expect(verdict.RoleActions).To(Equal(3))
+		Expect(verdict.Roles).To(Equal(2))
+	})
+
+	It("should allow the deletions within the limit", func() {
+		spec := operatorv1alpha1.RoleActionConfigSpec{
+			ServiceID: "product",
+			IAM: operatorv1alpha1.IAM{
+				Actions: []string{"read", "write", "delete"},
+				V2CustomRoles: []operatorv1alpha1.V2CustomRoles{
+					{Name: "viewer", Description: "Viewer", Actions: []string{"read"}},
+					{Name: "editor", Description: "Editor", Actions: []string{"read", "write"}},
+				},
+			},
+		}
+		maxDeletions := intstr.FromString("25%")
+		ops := Plan(spec, state)
+		verdict, err := Safeguard{MaxDeletions: &maxDeletions}.Check(state, ops)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(verdict.Allowed).To(Equal([]Operation{{Kind: RemoveProductAction, Action: "admin"}}))
+		Expect(verdict.Blocked).To(BeEmpty())
+	})
+
+	It("should let a percentage remove one action, but never the only one", func() {
+		maxDeletions := intstr.FromString("10%")
+		spec := operatorv1alpha1.RoleActionConfigSpec{
+			ServiceID: "product",
+			IAM:       operatorv1alpha1.IAM{Actions: []string{"read", "write", "delete"}},
+		}
+		ops := Plan(spec, state)
+		verdict, err := Safeguard{MaxDeletions: &maxDeletions}.Check(state, ops)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(verdict.Allowed).To(Equal([]Operation{{Kind: RemoveProductAction, Action: "admin"}}))
+		Expect(verdict.Blocked).To(BeEmpty())
+
+		By("holding back the removal of the only action of a product")
+		maxDeletions = intstr.FromString("50%")
+		state = &State{ProductRegistered: true, Actions: sets.New("read"), Roles: map[string]*RoleState{}}
+		ops = Plan(emptySpec, state)
+		verdict, err = Safeguard{MaxDeletions: &maxDeletions}.Check(state, ops)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(verdict.Allowed).To(BeEmpty())
+		Expect(verdict.Blocked).To(Equal([]Operation{{Kind: RemoveProductAction, Action: "read"}}))
+
+		By("holding back the removal of every action of a role")
+		state = &State{
+			ProductRegistered: true,
+			Actions:           sets.New("read"),
+			Roles: map[string]*RoleState{
+				"viewer": {UID: "1", Description: "Viewer", Actions: sets.New("product.read")},
+			},
+		}
+		spec = operatorv1alpha1.RoleActionConfigSpec{
+			ServiceID: "product",
+			IAM: operatorv1alpha1.IAM{
+				Actions:       []string{"read"},
+				V2CustomRoles: []operatorv1alpha1.V2CustomRoles{{Name: "viewer", Description: "Viewer", Actions: []string{}}},
+			},
+		}
+		ops = Plan(spec, state)
+		verdict, err = Safeguard{MaxDeletions: &maxDeletions}.Check(state, ops)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(verdict.Blocked).To(Equal([]Operation{{Kind: RemoveRoleAction, Role: "viewer", Action: "product.read"}}))
+
+		By("letting no action be removed with a limit of 0%")
+		maxDeletions = intstr.FromString("0%")
+		state = &State{ProductRegistered: true, Actions: sets.New("read", "write"), Roles: map[string]*RoleState{}}
+		spec = operatorv1alpha1.RoleActionConfigSpec{ServiceID: "product", IAM: operatorv1alpha1.IAM{Actions: []string{"read"}}}
+		ops = Plan(spec, state)
+		verdict, err = Safeguard{MaxDeletions: &maxDeletions}.Check(state, ops)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(verdict.Blocked).To(Equal([]Operation{{Kind: RemoveProductAction, Action: "write"}}))
+	})
+
+	It("should hold back every pruning operation above the limit", func() {
+		spec := operatorv1alpha1.RoleActionConfigSpec{
+			ServiceID: "product",
+			IAM: operatorv1alpha1.IAM{
+				Actions: []string{"read", "write", "delete", "admin", "audit"},
+				V2CustomRoles: []operatorv1alpha1.V2CustomRoles{
+					{Name: "viewer", Description: "Viewer", Actions: []string{"read"}},
+				},
+			},
+		}
+		maxDeletions := intstr.FromInt32(1)
+		ops := Plan(spec, state)
+		verdict, err := Safeguard{MaxDeletions: &maxDeletions}.Check(state, ops)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(verdict.Allowed).To(Equal([]Operation{{Kind: AddProductAction, Action: "audit"}}))
+		Expect(verdict.Blocked).To(Equal([]Operation{
+			{Kind: RemoveRoleAction, Role: "editor", Action: "product.read"},
+			{Kind: RemoveRoleAction, Role: "editor", Action: "product.write"},
+			{Kind: DeleteRole, Role: "editor"},
+		}))
+		Expect(verdict.Describe()).To(ContainSubstring("removing 0 product level actions, 2 role level actions and 1 custom roles"))
+	})
+
+	It("should hold back the deletion of more custom roles than allowed", func() {
+		state = &State{
+			ProductRegistered: true,
+			Actions:           sets.New("read"),
+			Roles: map[string]*RoleState{
+				"viewer": {UID: "1", Description: "Viewer", Actions: sets.New[string]()},
+				"editor": {UID: "2", Description: "Editor", Actions: sets.New[string]()},
+			},
+		}
+		spec := operatorv1alpha1.RoleActionConfigSpec{
+			ServiceID: "product",
+			IAM:       operatorv1alpha1.IAM{Actions: []string{"read"}, V2CustomRoles: []operatorv1alpha1.V2CustomRoles{}},
+		}
+		maxDeletions := intstr.FromInt32(1)
+		ops := Plan(spec, state)
+		verdict, err := Safeguard{MaxDeletions: &maxDeletions}.Check(state, ops)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(verdict.Allowed).To(BeEmpty())
+		Expect(verdict.Blocked).To(Equal([]Operation{
+			{Kind: DeleteRole, Role: "editor"},
+			{Kind: DeleteRole, Role: "viewer"},
+		}))
+		Expect(verdict.RoleActions).To(BeZero())
+		Expect(verdict.Roles).To(Equal(2))
+	})
+
+	It("should reject an invalid limit", func() {
+		maxDeletions := intstr.FromString("half")
+		ops := Plan(emptySpec, state)
+		_, err := Safeguard{MaxDeletions: &maxDeletions}.Check(state, ops)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -65,6 +65,8 @@ const (
 	RedisCACert = "account-iam-ui-redis-ca-cert"
 	// RedisCert is the name of Redis service Certificate and secret
 	RedisSVCCert = "account-iam-ui-redis-svc-tls-cert"
+	// RedisCAIssuer is the name of the Issuer of the Redis certificates
+	RedisCAIssuer = "account-iam-ui-redis-ca-issuer"
 	// PhaseRunning is the Running status
 	PhaseRunning = "Running"
 	// StatusCompleted is the Completed status
@@ -89,6 +91,8 @@ const (
 	EDBAPIGroupVersion = "postgresql.k8s.enterprisedb.io/v1"
 	// BootstrapSecret is the name of the secret for user management bootstrap
 	BootstrapSecret = "user-mgmt-bootstrap"
+	// PGPasswordKey is the key of the generated database password in the bootstrap secret
+	PGPasswordKey = "pgPassword"
 	// CreateDBJob is the name of the database creation job
 	CreateDBJob = "create-account-iam-db"
 	// DBMigrationJob is the name of the database migration job
@@ -131,4 +135,12 @@ const (
 	SkipAnnotation = "operator.ibm.com/ibm-user-management-operator.skip-update"
 	//HashedData is the key for checking the checksum of data section
 	HashedData string = "operator.ibm.com/ibm-user-management-operator.hashedData"
+	// PreviousOIDCIssuerAnnotation records on the CommonService CR the oidcIssuerURL of ibm-im-operator before the operator changed it
+	PreviousOIDCIssuerAnnotation = "operator.ibm.com/ibm-user-management-operator.previous-oidc-issuer-url"
+	// ConfiguredOIDCIssuerAnnotation records on the CommonService CR the oidcIssuerURL of ibm-im-operator the operator configured
+	ConfiguredOIDCIssuerAnnotation = "operator.ibm.com/ibm-user-management-operator.configured-oidc-issuer-url"
+	// AccountIAMFinalizer is the finalizer cleaning up the resources an AccountIAM leaves outside of its owner references
+	AccountIAMFinalizer = "operator.ibm.com/accountiam-cleanup"
+	// RoleActionConfigFinalizer is the finalizer deregistering a RoleActionConfig from Account IAM
+	RoleActionConfigFinalizer = "operator.ibm.com/roleactionconfig-cleanup"
 )
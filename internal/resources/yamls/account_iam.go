@@ -292,6 +292,9 @@ metadata:
     component-name: iam-services
     for-product: all
 spec:
+{{- if .Host }}
+  host: {{ .Host }}
+{{- end }}
   port:
     targetPort: 9445-tcp
   tls:
@@ -60,13 +60,13 @@ metadata:
     bcdr-candidate: t
     component-name: iam-services
 stringData:
-  pg_jdbc_host: common-service-db-rw
-  pg_jdbc_port: "5432"
-  pg_db_name: account_iam
-  pg_db_schema: accountiam
-  pg_db_user: user_accountiam
+  pg_jdbc_host: {{ .DBHost }}
+  pg_jdbc_port: "{{ .DBPort }}"
+  pg_db_name: {{ .DBName }}
+  pg_db_schema: {{ .DBSchema }}
+  pg_db_user: {{ .DBUser }}
   pg_jdbc_password_jndi: "jdbc/iamdatasource"
-  pg_ssl_mode: prefer
+  pg_ssl_mode: {{ .DBSSLMode }}
   GLOBAL_ACCOUNT_IDP: {{ .GlobalAccountIDP }}
 data:
   pgPassword: {{ .PGPassword }}
@@ -17,8 +17,18 @@ spec:
      accept: true
   cert_name: account-iam-ui-redis-svc-tls-cert
   shutdown: false
-  scale_config: medium
+  scale_config: {{.RedisCRScaleConfig}}
   version: {{.RedisCRVersion}}
+{{- if .RedisCRPersistence }}
+  persistence:
+    enabled: true
+{{- if .RedisCRStorageClass }}
+    storage_class: {{.RedisCRStorageClass}}
+{{- end }}
+{{- if .RedisCRStorageSize }}
+    size: {{.RedisCRStorageSize}}
+{{- end }}
+{{- end }}
 `
 
 var REDIS_CA_ISSUER = `
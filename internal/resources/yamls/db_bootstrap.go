@@ -14,6 +14,17 @@ spec:
       - name: postgres
         image: RELATED_IMAGE_MCSP_UTILS
         command: ["/bin/bash", "/db-init/create_db.sh"]
+        env:
+        - name: PGHOST
+          value: "{{ .DBHost }}"
+        - name: PGPORT
+          value: "{{ .DBPort }}"
+        - name: PGSSLMODE
+          value: "{{ .DBSSLMode }}"
+{{- if .DBCASecret }}
+        - name: PGSSLROOTCERT
+          value: /db-ca/ca.crt
+{{- end }}
         volumeMounts:
         - name: psql-credentials
           mountPath: /psql-credentials
@@ -21,12 +32,16 @@ spec:
           mountPath: /db-password
         - name: data-volume
           mountPath: /data
+{{- if .DBCASecret }}
+        - name: db-ca
+          mountPath: /db-ca
+{{- end }}
       restartPolicy: OnFailure
       serviceAccountName: user-mgmt-operand-serviceaccount
       volumes:
       - name: psql-credentials
         secret:
-          secretName: common-service-db-superuser
+          secretName: {{ .DBSuperuserSecret }}
           items:
           - key: username
             path: username
@@ -35,13 +50,22 @@ spec:
           defaultMode: 420  
       - name: db-password
         secret:
-          secretName: user-mgmt-bootstrap
+          secretName: {{ .DBPasswordSecret }}
           items:
-          - key: pgPassword
+          - key: {{ .DBPasswordKey }}
             path: password
           defaultMode: 420  
       - name: data-volume
         emptyDir: {}      
+{{- if .DBCASecret }}
+      - name: db-ca
+        secret:
+          secretName: {{ .DBCASecret }}
+          items:
+          - key: {{ .DBCAKey }}
+            path: ca.crt
+          defaultMode: 420
+{{- end }}
   backoffLimit: 4
 
 `
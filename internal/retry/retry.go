@@ -1,6 +1,8 @@
 package retry
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -16,13 +18,17 @@ func (p permanentError) Error() string {
 	return p.err.Error()
 }
 
+func (p permanentError) Unwrap() error {
+	return p.err
+}
+
 func NewPermanentError(err error) error {
 	return permanentError{err: err}
 }
 
+// IsPermanentError reports whether err, or any error it wraps, is permanent
 func IsPermanentError(err error) bool {
-	_, ok := err.(permanentError)
-	return ok
+	return errors.As(err, &permanentError{})
 }
 
 type Retry struct {
@@ -31,7 +37,9 @@ type Retry struct {
 	BackoffMaxRetries uint64
 }
 
-func (r *Retry) RetryHandler(op backoff.Operation) error {
+// RetryHandler runs op until it succeeds, fails with a permanent error or runs out of retries.
+// The backoff between attempts is interrupted when ctx is done, and the context error is returned.
+func (r *Retry) RetryHandler(ctx context.Context, op backoff.Operation) error {
 	b := backoff.NewExponentialBackOff()
 	b.InitialInterval = r.BackoffInterval
 	b.Multiplier = r.BackoffMultiplier
@@ -61,5 +69,5 @@ func (r *Retry) RetryHandler(op backoff.Operation) error {
 			Msg("retry attempt failed")
 	}
 
-	return backoff.RetryNotify(wrappedOp, backoff.WithMaxRetries(b, r.BackoffMaxRetries), notify)
+	return backoff.RetryNotify(wrappedOp, backoff.WithContext(backoff.WithMaxRetries(b, r.BackoffMaxRetries), ctx), notify)
 }